@@ -0,0 +1,165 @@
+package xerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+var (
+	// ErrRetryFailed is the Value of the error returned by [Retry] when the
+	// operation did not succeed, unless the context was done.
+	ErrRetryFailed = errors.New("xerr: retry failed")
+
+	// ErrAttemptFailed is the Value of the errors wrapping each failed
+	// attempt of [Retry].
+	ErrAttemptFailed = errors.New("xerr: attempt failed")
+)
+
+// Clock waits between the attempts of [Retry]. It can be replaced in tests to
+// control the passing of time.
+type Clock interface {
+	// After waits for the duration d to elapse and then sends the current
+	// time on the returned channel, like [time.After].
+	After(d time.Duration) <-chan time.Time
+}
+
+// systemClock is the [Clock] of the system.
+type systemClock struct{}
+
+// After implements [Clock] with [time.After].
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// RetryPolicy configures the attempts of [Retry]. The delay before the
+// attempt n+1 is InitialDelay * Multiplier^(n-1), bounded by MaxDelay and
+// randomized by Jitter, or the RetryAfter of the failed attempt if it is
+// longer (see [RetryAfter]).
+type RetryPolicy struct {
+	MaxAttempts  int           // Maximum number of attempts, including the first one, unlimited if lower than 1
+	InitialDelay time.Duration // Delay before the second attempt
+	MaxDelay     time.Duration // Maximum delay between two attempts, unbounded if 0
+	Multiplier   float64       // Growth factor of the delay after each attempt, 2 if lower than 1
+	Jitter       float64       // Fraction of the delay randomized, between 0 and 1
+	Clock        Clock         // Clock waiting between the attempts, the system clock if nil
+}
+
+// DefaultRetryPolicy is a [RetryPolicy] making at most 3 attempts, the second
+// one after about 100ms, the third one after about 200ms.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:  3,
+	InitialDelay: 100 * time.Millisecond,
+	MaxDelay:     10 * time.Second,
+	Multiplier:   2,
+	Jitter:       0.2,
+}
+
+// delay returns the delay before the attempt following the failed attempt
+// number attempt, r being a random number in [0, 1).
+func (p RetryPolicy) delay(attempt int, r float64) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	delay := float64(p.InitialDelay) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxDelay > 0 {
+		delay = math.Min(delay, float64(p.MaxDelay))
+	}
+	if jitter := math.Min(math.Max(p.Jitter, 0), 1); jitter > 0 {
+		delay *= 1 + jitter*(2*r-1)
+	}
+
+	if delay >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(delay)
+}
+
+// clock returns the Clock of the policy, or the system clock.
+func (p RetryPolicy) clock() Clock {
+	if p.Clock == nil {
+		return systemClock{}
+	}
+	return p.Clock
+}
+
+// RetryAttempt is the Details of the error wrapping a failed attempt of
+// [Retry].
+type RetryAttempt struct {
+	Attempt int           `json:"attempt"` // Number of the attempt, from 1
+	Delay   time.Duration `json:"delay"`   // Delay waited before the attempt
+}
+
+// Retry calls fn until it succeeds, the error it returns is not retryable
+// (see [Retryable]), the maximum number of attempts of policy is reached, or
+// ctx is done, waiting between the attempts as configured by policy. It
+// returns nil as soon as fn succeeds.
+//
+// Otherwise, the returned error tells the whole retry story: its Value is
+// [ErrRetryFailed], or the error of ctx if it is done, and its Causes are the
+// failed attempts, in order. Each attempt is wrapped by an error whose Value
+// is [ErrAttemptFailed], whose Details is a [RetryAttempt] and whose Prev is
+// the error returned by fn, so that [Err.Is] and [Err.As] see the errors of
+// every attempt.
+//
+// Example:
+//
+//	err := xerr.Retry(ctx, xerr.DefaultRetryPolicy, func() *xerr.Err {
+//		return fetchUser(ctx, 42)
+//	})
+func Retry(ctx context.Context, policy RetryPolicy, fn func() *Err) *Err {
+	clock := policy.clock()
+
+	var attempts []*Err
+	var delay time.Duration
+	value, msg := error(ErrRetryFailed), ""
+
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			value, msg = err, "retry stopped after "+countAttempts(len(attempts))
+			break
+		}
+
+		err := fn()
+		if err == nil {
+			return nil
+		}
+		attempts = append(attempts, Make(ErrAttemptFailed,
+			WithMsg(fmt.Sprintf("attempt %d", attempt)),
+			WithDetails(RetryAttempt{Attempt: attempt, Delay: delay}),
+			WithPrev(err),
+			WithoutStack(),
+			WithSkip(2),
+		))
+
+		if !Retryable(err) {
+			msg = fmt.Sprintf("attempt %d is not retryable", attempt)
+			break
+		}
+		if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+			msg = "giving up after " + countAttempts(attempt)
+			break
+		}
+
+		delay = max(policy.delay(attempt, rand.Float64()), RetryAfter(err))
+		select {
+		case <-clock.After(delay):
+		case <-ctx.Done():
+		}
+	}
+
+	return Make(value, WithMsg(msg), WithCauses(attempts...), WithSkip(2))
+}
+
+// countAttempts returns the number of attempts n in words, e.g. "1 attempt".
+func countAttempts(n int) string {
+	if n == 1 {
+		return "1 attempt"
+	}
+	return fmt.Sprintf("%d attempts", n)
+}
@@ -0,0 +1,280 @@
+package xerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock is a [Clock] recording the delays waited, which elapse at once.
+type fakeClock struct {
+	delays []time.Duration
+	wait   func(d time.Duration) // called before the delay elapses, if not nil
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.delays = append(c.delays, d)
+	if c.wait != nil {
+		c.wait(d)
+	}
+
+	ch := make(chan time.Time, 1)
+	ch <- time.Time{}
+	return ch
+}
+
+// blockingClock is a [Clock] whose delays never elapse.
+type blockingClock struct{}
+
+func (blockingClock) After(time.Duration) <-chan time.Time { return nil }
+
+// newTestPolicy returns a policy without jitter using clock.
+func newTestPolicy(clock Clock) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:  4,
+		InitialDelay: 100 * time.Millisecond,
+		MaxDelay:     time.Second,
+		Multiplier:   3,
+		Clock:        clock,
+	}
+}
+
+// failing returns a function failing with the errors of errs, in order, and
+// succeeding once they are exhausted.
+func failing(errs ...*Err) (fn func() *Err, calls *int) {
+	calls = new(int)
+	return func() *Err {
+		*calls++
+		if *calls > len(errs) {
+			return nil
+		}
+		return errs[*calls-1]
+	}, calls
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of RetryPolicy
+//
+// ----------------------------------------------------------------------------
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := newTestPolicy(nil)
+
+	assert.Equal(t, 100*time.Millisecond, p.delay(1, 0.5))
+	assert.Equal(t, 300*time.Millisecond, p.delay(2, 0.5))
+	assert.Equal(t, 900*time.Millisecond, p.delay(3, 0.5))
+	assert.Equal(t, time.Second, p.delay(4, 0.5), "bounded by MaxDelay")
+	assert.Equal(t, time.Second, p.delay(1000, 0.5), "no overflow")
+}
+
+func TestRetryPolicy_Delay_Defaults(t *testing.T) {
+	p := RetryPolicy{InitialDelay: time.Second}
+
+	assert.Equal(t, 4*time.Second, p.delay(3, 0), "multiplier defaults to 2")
+	assert.Equal(t, time.Duration(1<<63-1), p.delay(10000, 0), "unbounded")
+}
+
+func TestRetryPolicy_Delay_Jitter(t *testing.T) {
+	p := newTestPolicy(nil)
+	p.Jitter = 0.2
+
+	assert.Equal(t, 80*time.Millisecond, p.delay(1, 0))
+	assert.Equal(t, 100*time.Millisecond, p.delay(1, 0.5))
+	assert.Equal(t, 119*time.Millisecond, p.delay(1, 0.995).Truncate(time.Millisecond))
+	assert.Equal(t, 800*time.Millisecond, p.delay(5, 0), "jitter applies after the bound")
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Retry()
+//
+// ----------------------------------------------------------------------------
+
+func TestRetry_Success(t *testing.T) {
+	clock := &fakeClock{}
+	fn, calls := failing()
+
+	assert.Nil(t, Retry(context.Background(), newTestPolicy(clock), fn))
+	assert.Equal(t, 1, *calls)
+	assert.Empty(t, clock.delays)
+}
+
+func TestRetry_SuccessAfterFailures(t *testing.T) {
+	clock := &fakeClock{}
+	fn, calls := failing(
+		Make(errTimeout, WithRetryable(true)),
+		Make(errTimeout, WithRetryable(true)),
+	)
+
+	assert.Nil(t, Retry(context.Background(), newTestPolicy(clock), fn))
+	assert.Equal(t, 3, *calls)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 300 * time.Millisecond}, clock.delays)
+}
+
+func TestRetry_Level(t *testing.T) {
+	clock := &fakeClock{}
+	fn, _ := failing(
+		Make(errTimeout, WithRetryable(true)),
+		Make(errTimeout, WithSeverity(SeverityCritical), WithRetryable(true)),
+		Make(errTimeout, WithRetryable(true)),
+		Make(errTimeout, WithRetryable(true)),
+	)
+
+	err := Retry(context.Background(), newTestPolicy(clock), fn)
+
+	require.NotNil(t, err)
+	assert.Equal(t, SeverityUnspecified, err.Severity)
+	assert.Equal(t, SeverityCritical, err.MaxSeverity())
+	assert.Equal(t, LevelCritical, Level(err))
+}
+
+func TestRetry_MaxAttempts(t *testing.T) {
+	clock := &fakeClock{}
+	fn, calls := failing(
+		Make(errTimeout, WithMsg("try 1"), WithRetryable(true)),
+		Make(errTimeout, WithMsg("try 2"), WithRetryable(true)),
+		Make(errTimeout, WithMsg("try 3"), WithRetryable(true)),
+		Make(errTimeout, WithMsg("try 4"), WithRetryable(true)),
+		Make(errTimeout, WithMsg("try 5"), WithRetryable(true)),
+	)
+
+	err := Retry(context.Background(), newTestPolicy(clock), fn)
+
+	require.NotNil(t, err)
+	assert.Equal(t, 4, *calls)
+	assert.Len(t, clock.delays, 3)
+	assert.Equal(t, ErrRetryFailed, err.Value)
+	assert.Equal(t, "giving up after 4 attempts", err.Msg)
+	assert.True(t, err.Is(errTimeout))
+
+	require.Len(t, err.Causes, 4)
+	var attempts []RetryAttempt
+	for i, cause := range err.Causes {
+		attempts = append(attempts, cause.Details.(RetryAttempt))
+		assert.Equal(t, ErrAttemptFailed, cause.Value)
+		assert.Equal(t, fmt.Sprintf("attempt %d", i+1), cause.Msg)
+		assert.Equal(t, fmt.Sprintf("try %d", i+1), cause.Prev.Msg)
+	}
+	assert.Equal(t, []RetryAttempt{
+		{Attempt: 1},
+		{Attempt: 2, Delay: 100 * time.Millisecond},
+		{Attempt: 3, Delay: 300 * time.Millisecond},
+		{Attempt: 4, Delay: 900 * time.Millisecond},
+	}, attempts)
+}
+
+func TestRetry_Unlimited(t *testing.T) {
+	clock := &fakeClock{}
+	errs := make([]*Err, 20)
+	for i := range errs {
+		errs[i] = Make(errTimeout, WithTemporary(true))
+	}
+	fn, calls := failing(errs...)
+
+	policy := newTestPolicy(clock)
+	policy.MaxAttempts = 0
+
+	assert.Nil(t, Retry(context.Background(), policy, fn))
+	assert.Equal(t, 21, *calls)
+}
+
+func TestRetry_NotRetryable(t *testing.T) {
+	clock := &fakeClock{}
+	fn, calls := failing(
+		Make(errTimeout, WithRetryable(true)),
+		NewSimple(errFetchFailed, "not found", nil),
+	)
+
+	err := Retry(context.Background(), newTestPolicy(clock), fn)
+
+	require.NotNil(t, err)
+	assert.Equal(t, 2, *calls)
+	assert.Len(t, clock.delays, 1)
+	assert.Equal(t, "attempt 2 is not retryable", err.Msg)
+	assert.Len(t, err.Causes, 2)
+	assert.True(t, err.Is(errFetchFailed))
+}
+
+func TestRetry_RetryAfter(t *testing.T) {
+	clock := &fakeClock{}
+	fn, _ := failing(
+		Make(errTimeout, WithRetryable(true), WithRetryAfter(5*time.Second)),
+		Make(errTimeout, WithRetryable(true), WithRetryAfter(time.Millisecond)),
+	)
+
+	assert.Nil(t, Retry(context.Background(), newTestPolicy(clock), fn))
+	assert.Equal(t, []time.Duration{5 * time.Second, 300 * time.Millisecond}, clock.delays)
+}
+
+func TestRetry_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clock := &fakeClock{wait: func(time.Duration) { cancel() }}
+	fn, calls := failing(
+		Make(errTimeout, WithRetryable(true)),
+		Make(errTimeout, WithRetryable(true)),
+	)
+
+	err := Retry(ctx, newTestPolicy(clock), fn)
+
+	require.NotNil(t, err)
+	assert.Equal(t, 1, *calls)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.True(t, errors.Is(err, errTimeout))
+	assert.Equal(t, "retry stopped after 1 attempt", err.Msg)
+}
+
+func TestRetry_ContextDoneBeforeFirstAttempt(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	fn, calls := failing()
+
+	err := Retry(ctx, newTestPolicy(blockingClock{}), fn)
+
+	require.NotNil(t, err)
+	assert.Equal(t, 0, *calls)
+	assert.Equal(t, context.Canceled, err.Value)
+	assert.Equal(t, "retry stopped after 0 attempts", err.Msg)
+	assert.Empty(t, err.Causes)
+}
+
+func TestRetry_ContextDoneWhileWaiting(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	fn, calls := failing(Make(errTimeout, WithRetryable(true)), Make(errTimeout, WithRetryable(true)))
+
+	err := Retry(ctx, newTestPolicy(blockingClock{}), fn)
+
+	require.NotNil(t, err)
+	assert.Equal(t, 1, *calls)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestRetry_CallSite(t *testing.T) {
+	fn, _ := failing(NewSimple(errFetchFailed, "", nil))
+
+	err := Retry(context.Background(), newTestPolicy(&fakeClock{}), fn)
+
+	require.NotNil(t, err)
+	assert.True(t, strings.HasSuffix(err.File, "backoff_test.go"))
+	assert.True(t, strings.HasSuffix(err.Causes[0].File, "backoff_test.go"))
+	assert.Nil(t, err.Causes[0].StackTrace)
+}
+
+func TestRetry_SystemClock(t *testing.T) {
+	fn, calls := failing(Make(errTimeout, WithRetryable(true)))
+	policy := RetryPolicy{MaxAttempts: 2, InitialDelay: time.Millisecond}
+
+	assert.Nil(t, Retry(context.Background(), policy, fn))
+	assert.Equal(t, 2, *calls)
+}
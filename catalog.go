@@ -0,0 +1,258 @@
+package xerr
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Category is a canonical error category, modeled after the gRPC status
+// codes. Values from 1 match the numeric gRPC codes.
+type Category int
+
+// Canonical error categories.
+const (
+	CategoryUnspecified Category = iota
+	CategoryCancelled
+	CategoryUnknown
+	CategoryInvalidArgument
+	CategoryDeadlineExceeded
+	CategoryNotFound
+	CategoryAlreadyExists
+	CategoryPermissionDenied
+	CategoryResourceExhausted
+	CategoryFailedPrecondition
+	CategoryAborted
+	CategoryOutOfRange
+	CategoryUnimplemented
+	CategoryInternal
+	CategoryUnavailable
+	CategoryDataLoss
+	CategoryUnauthenticated
+)
+
+// categories are the canonical names and HTTP statuses of the categories.
+var categories = [...]struct {
+	name   string
+	status int
+}{
+	CategoryUnspecified:        {"UNSPECIFIED", http.StatusInternalServerError},
+	CategoryCancelled:          {"CANCELLED", 499},
+	CategoryUnknown:            {"UNKNOWN", http.StatusInternalServerError},
+	CategoryInvalidArgument:    {"INVALID_ARGUMENT", http.StatusBadRequest},
+	CategoryDeadlineExceeded:   {"DEADLINE_EXCEEDED", http.StatusGatewayTimeout},
+	CategoryNotFound:           {"NOT_FOUND", http.StatusNotFound},
+	CategoryAlreadyExists:      {"ALREADY_EXISTS", http.StatusConflict},
+	CategoryPermissionDenied:   {"PERMISSION_DENIED", http.StatusForbidden},
+	CategoryResourceExhausted:  {"RESOURCE_EXHAUSTED", http.StatusTooManyRequests},
+	CategoryFailedPrecondition: {"FAILED_PRECONDITION", http.StatusBadRequest},
+	CategoryAborted:            {"ABORTED", http.StatusConflict},
+	CategoryOutOfRange:         {"OUT_OF_RANGE", http.StatusBadRequest},
+	CategoryUnimplemented:      {"UNIMPLEMENTED", http.StatusNotImplemented},
+	CategoryInternal:           {"INTERNAL", http.StatusInternalServerError},
+	CategoryUnavailable:        {"UNAVAILABLE", http.StatusServiceUnavailable},
+	CategoryDataLoss:           {"DATA_LOSS", http.StatusInternalServerError},
+	CategoryUnauthenticated:    {"UNAUTHENTICATED", http.StatusUnauthorized},
+}
+
+// String returns the canonical name of the category, e.g. "NOT_FOUND".
+func (c Category) String() string {
+	if c < 0 || int(c) >= len(categories) {
+		return categories[CategoryUnspecified].name
+	}
+	return categories[c].name
+}
+
+// HTTPStatus returns the HTTP status conventionally associated with the
+// category, e.g. 404 for [CategoryNotFound].
+func (c Category) HTTPStatus() int {
+	if c < 0 || int(c) >= len(categories) {
+		return categories[CategoryUnspecified].status
+	}
+	return categories[c].status
+}
+
+// CodeInfo is the metadata of an error code declared in a [Catalog].
+type CodeInfo struct {
+	Code       int      // Error code, must not be 0
+	Name       string   // Symbolic name, e.g. "USER_NOT_FOUND"
+	Message    string   // Default message of the errors with this code
+	HTTPStatus int      // HTTP status, 0 to use the one of the Category
+	Category   Category // Canonical category
+	Severity   Severity // Severity level
+	Retryable  *bool    // Whether the failed operation is worth retrying, nil if unspecified
+}
+
+// Status returns the HTTP status of the code: HTTPStatus if set, or the
+// status of the Category otherwise.
+func (i CodeInfo) Status() int {
+	if i.HTTPStatus != 0 {
+		return i.HTTPStatus
+	}
+	return i.Category.HTTPStatus()
+}
+
+// Catalog is a set of error codes, each declared once with its metadata.
+// A Catalog is safe for concurrent use, and its read methods are nil-safe.
+//
+// Example:
+//
+//	const CodeUserNotFound = 1001
+//
+//	var Codes = xerr.NewCatalog().MustDefine(xerr.CodeInfo{
+//		Code:     CodeUserNotFound,
+//		Name:     "USER_NOT_FOUND",
+//		Message:  "User not found",
+//		Category: xerr.CategoryNotFound,
+//	})
+//
+//	err := Codes.New(CodeUserNotFound, ErrNotFound)
+type Catalog struct {
+	mu     sync.RWMutex
+	byCode map[int]CodeInfo
+	byName map[string]int
+}
+
+// NewCatalog returns an empty catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		byCode: make(map[int]CodeInfo),
+		byName: make(map[string]int),
+	}
+}
+
+// Define declares the codes of infos in the catalog. It returns an error
+// wrapping [ErrAlreadyRegistered] if a code or a name is already declared,
+// and [ErrInvalidRegistration] if a code is 0 or a name is empty. Codes are
+// declared in order until the first error.
+func (c *Catalog) Define(infos ...CodeInfo) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, info := range infos {
+		if info.Code == 0 || info.Name == "" {
+			return fmt.Errorf("%w: code %d with name %q", ErrInvalidRegistration, info.Code, info.Name)
+		}
+		if other, ok := c.byCode[info.Code]; ok {
+			return fmt.Errorf("%w: code %d with name %q", ErrAlreadyRegistered, info.Code, other.Name)
+		}
+		if other, ok := c.byName[info.Name]; ok {
+			return fmt.Errorf("%w: name %q with code %d", ErrAlreadyRegistered, info.Name, other)
+		}
+
+		c.byCode[info.Code] = info
+		c.byName[info.Name] = info.Code
+	}
+
+	return nil
+}
+
+// MustDefine is like [Catalog.Define] but panics if a declaration fails. It
+// returns the catalog to allow declaring codes in a variable initializer.
+func (c *Catalog) MustDefine(infos ...CodeInfo) *Catalog {
+	if err := c.Define(infos...); err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Lookup returns the metadata of code.
+func (c *Catalog) Lookup(code int) (CodeInfo, bool) {
+	if c == nil {
+		return CodeInfo{}, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	info, ok := c.byCode[code]
+	return info, ok
+}
+
+// LookupName returns the metadata of the code declared with name.
+func (c *Catalog) LookupName(name string) (CodeInfo, bool) {
+	if c == nil {
+		return CodeInfo{}, false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	code, ok := c.byName[name]
+	if !ok {
+		return CodeInfo{}, false
+	}
+	return c.byCode[code], true
+}
+
+// Codes returns the metadata of all the codes of the catalog, sorted by code.
+func (c *Catalog) Codes() []CodeInfo {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	codes := slices.Sorted(maps.Keys(c.byCode))
+	infos := make([]CodeInfo, 0, len(codes))
+	for _, code := range codes {
+		infos = append(infos, c.byCode[code])
+	}
+	return infos
+}
+
+// New creates a new *Err with the provided error value and code, whose Msg
+// is the default message of the code in the catalog, whose Severity is the
+// one of the code, and which is classified as retryable or not if the code
+// declares it (see [WithRetryable]), overriding the Prev chain. Otherwise, the
+// classification is left to the Prev chain. The options are applied after the
+// catalog defaults, so they can override them. Returns nil if value is nil.
+//
+// A code not declared in the catalog is still set on the error, without a
+// default message, severity nor retry classification. The call site is the
+// caller of New, as with [Make].
+func (c *Catalog) New(code int, value error, opts ...Option) *Err {
+	info, ok := c.Lookup(code)
+
+	defaults := []Option{
+		WithCode(code),
+		WithMsg(info.Message),
+	}
+	if ok {
+		defaults = append(defaults, WithSeverity(info.Severity))
+	}
+	if ok && info.Retryable != nil {
+		defaults = append(defaults, WithRetryable(*info.Retryable))
+	}
+
+	return build(value, newOptions(append(defaults, opts...)))
+}
+
+// defaultCatalog is the catalog used to resolve the symbolic names of codes.
+var defaultCatalog atomic.Pointer[Catalog]
+
+// SetDefaultCatalog sets the catalog used to emit the symbolic name of codes
+// in [Err.Error] and [Err.MarshalJSON], and returns the previous one. A nil
+// catalog disables code names.
+func SetDefaultCatalog(c *Catalog) *Catalog {
+	return defaultCatalog.Swap(c)
+}
+
+// DefaultCatalog returns the catalog set with [SetDefaultCatalog], or nil.
+func DefaultCatalog() *Catalog {
+	return defaultCatalog.Load()
+}
+
+// codeName returns the symbolic name of code in the default catalog, or an
+// empty string.
+func codeName(code int) string {
+	if code == 0 {
+		return ""
+	}
+	info, _ := DefaultCatalog().Lookup(code)
+	return info.Name
+}
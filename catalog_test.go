@@ -0,0 +1,301 @@
+package xerr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testCodeUserNotFound = 1001
+	testCodeTimeout      = 1002
+)
+
+// newTestCatalog returns a catalog with the test codes.
+func newTestCatalog() *Catalog {
+	return NewCatalog().MustDefine(
+		CodeInfo{
+			Code:       testCodeUserNotFound,
+			Name:       "USER_NOT_FOUND",
+			Message:    "User not found",
+			HTTPStatus: http.StatusNotFound,
+			Category:   CategoryNotFound,
+			Severity:   SeverityInfo,
+			Retryable:  new(false),
+		},
+		CodeInfo{
+			Code:      testCodeTimeout,
+			Name:      "TIMEOUT",
+			Message:   "Operation timed out",
+			Category:  CategoryDeadlineExceeded,
+			Severity:  SeverityError,
+			Retryable: new(true),
+		},
+	)
+}
+
+// setDefaultCatalog sets the default catalog for the duration of the test.
+func setDefaultCatalog(t *testing.T, c *Catalog) {
+	t.Helper()
+
+	prev := SetDefaultCatalog(c)
+	t.Cleanup(func() { SetDefaultCatalog(prev) })
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Category
+//
+// ----------------------------------------------------------------------------
+
+func TestCategory_String(t *testing.T) {
+	assert.Equal(t, "UNSPECIFIED", CategoryUnspecified.String())
+	assert.Equal(t, "NOT_FOUND", CategoryNotFound.String())
+	assert.Equal(t, "UNAUTHENTICATED", CategoryUnauthenticated.String())
+	assert.Equal(t, "UNSPECIFIED", Category(-1).String())
+	assert.Equal(t, "UNSPECIFIED", Category(100).String())
+}
+
+func TestCategory_HTTPStatus(t *testing.T) {
+	assert.Equal(t, http.StatusNotFound, CategoryNotFound.HTTPStatus())
+	assert.Equal(t, http.StatusBadRequest, CategoryInvalidArgument.HTTPStatus())
+	assert.Equal(t, http.StatusServiceUnavailable, CategoryUnavailable.HTTPStatus())
+	assert.Equal(t, http.StatusInternalServerError, CategoryUnspecified.HTTPStatus())
+	assert.Equal(t, http.StatusInternalServerError, Category(100).HTTPStatus())
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of CodeInfo
+//
+// ----------------------------------------------------------------------------
+
+func TestCodeInfo_Status(t *testing.T) {
+	assert.Equal(t, http.StatusGone, CodeInfo{HTTPStatus: http.StatusGone, Category: CategoryNotFound}.Status())
+	assert.Equal(t, http.StatusNotFound, CodeInfo{Category: CategoryNotFound}.Status())
+	assert.Equal(t, http.StatusInternalServerError, CodeInfo{}.Status())
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Catalog.Define()
+//
+// ----------------------------------------------------------------------------
+
+func TestCatalog_Define(t *testing.T) {
+	c := newTestCatalog()
+
+	info, ok := c.Lookup(testCodeUserNotFound)
+	assert.True(t, ok)
+	assert.Equal(t, "USER_NOT_FOUND", info.Name)
+	assert.Equal(t, "User not found", info.Message)
+
+	info, ok = c.LookupName("TIMEOUT")
+	assert.True(t, ok)
+	assert.Equal(t, testCodeTimeout, info.Code)
+	assert.Equal(t, new(true), info.Retryable)
+}
+
+func TestCatalog_Define_Duplicate(t *testing.T) {
+	c := newTestCatalog()
+
+	err := c.Define(CodeInfo{Code: testCodeUserNotFound, Name: "OTHER"})
+	assert.ErrorIs(t, err, ErrAlreadyRegistered)
+
+	err = c.Define(CodeInfo{Code: 2000, Name: "TIMEOUT"})
+	assert.ErrorIs(t, err, ErrAlreadyRegistered)
+
+	_, ok := c.LookupName("OTHER")
+	assert.False(t, ok)
+	_, ok = c.Lookup(2000)
+	assert.False(t, ok)
+}
+
+func TestCatalog_Define_Invalid(t *testing.T) {
+	c := NewCatalog()
+
+	assert.ErrorIs(t, c.Define(CodeInfo{Name: "ZERO"}), ErrInvalidRegistration)
+	assert.ErrorIs(t, c.Define(CodeInfo{Code: 1}), ErrInvalidRegistration)
+}
+
+func TestCatalog_MustDefine_Panics(t *testing.T) {
+	c := newTestCatalog()
+
+	assert.Panics(t, func() { c.MustDefine(CodeInfo{Code: testCodeTimeout, Name: "OTHER"}) })
+}
+
+func TestCatalog_Define_Concurrent(t *testing.T) {
+	c := NewCatalog()
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Go(func() {
+			assert.NoError(t, c.Define(CodeInfo{Code: i + 1, Name: fmt.Sprintf("CODE_%d", i+1)}))
+			_, ok := c.Lookup(i + 1)
+			assert.True(t, ok)
+		})
+	}
+	wg.Wait()
+
+	assert.Len(t, c.Codes(), 10)
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Catalog lookups
+//
+// ----------------------------------------------------------------------------
+
+func TestCatalog_Lookup_Unknown(t *testing.T) {
+	c := newTestCatalog()
+
+	_, ok := c.Lookup(404)
+	assert.False(t, ok)
+	_, ok = c.LookupName("UNKNOWN")
+	assert.False(t, ok)
+}
+
+func TestCatalog_Nil(t *testing.T) {
+	var c *Catalog
+
+	_, ok := c.Lookup(testCodeUserNotFound)
+	assert.False(t, ok)
+	_, ok = c.LookupName("USER_NOT_FOUND")
+	assert.False(t, ok)
+	assert.Nil(t, c.Codes())
+}
+
+func TestCatalog_Codes(t *testing.T) {
+	codes := newTestCatalog().Codes()
+
+	assert.Len(t, codes, 2)
+	assert.Equal(t, testCodeUserNotFound, codes[0].Code)
+	assert.Equal(t, testCodeTimeout, codes[1].Code)
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Catalog.New()
+//
+// ----------------------------------------------------------------------------
+
+func TestCatalog_New(t *testing.T) {
+	c := newTestCatalog()
+	sentinel := errors.New("not found")
+
+	_, _, wantLine, _ := runtime.Caller(0)
+	wantLine += 2
+	e := c.New(testCodeUserNotFound, sentinel)
+
+	assert.Same(t, sentinel, e.Value)
+	assert.Equal(t, testCodeUserNotFound, e.Code)
+	assert.Equal(t, "User not found", e.Msg)
+	assert.Equal(t, SeverityInfo, e.Severity)
+	assert.True(t, strings.Contains(e.File, "catalog_test.go"))
+	assert.Equal(t, wantLine, e.Line)
+	assert.Equal(t, e.Line, e.Frames()[0].Line)
+}
+
+func TestCatalog_New_WithOptions(t *testing.T) {
+	c := newTestCatalog()
+	prev := NewSimple(errors.New("root"), "", nil)
+
+	e := c.New(testCodeUserNotFound, errors.New("not found"),
+		WithMsg("User 42 not found"),
+		WithDetails(42),
+		WithPrev(prev),
+	)
+
+	assert.Equal(t, testCodeUserNotFound, e.Code)
+	assert.Equal(t, "User 42 not found", e.Msg)
+	assert.Equal(t, 42, e.Details)
+	assert.Equal(t, prev, e.Prev)
+}
+
+func TestCatalog_New_WithSkip(t *testing.T) {
+	e := newTestCatalog().New(testCodeUserNotFound, errors.New("not found"), WithSkip(0))
+
+	// skip=0 → runtime.Caller(0) points inside catalog.go, not at the call site
+	assert.True(t, strings.Contains(e.File, "catalog.go"))
+}
+
+func TestCatalog_New_UnknownCode(t *testing.T) {
+	e := newTestCatalog().New(404, errors.New("not found"))
+
+	assert.Equal(t, 404, e.Code)
+	assert.Equal(t, "", e.Msg)
+}
+
+func TestCatalog_New_NilValue(t *testing.T) {
+	assert.Nil(t, newTestCatalog().New(testCodeUserNotFound, nil))
+
+	var c *Catalog
+	e := c.New(testCodeUserNotFound, errors.New("not found"))
+	assert.Equal(t, testCodeUserNotFound, e.Code)
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of code names
+//
+// ----------------------------------------------------------------------------
+
+func TestSetDefaultCatalog(t *testing.T) {
+	c := newTestCatalog()
+	setDefaultCatalog(t, c)
+
+	assert.Same(t, c, DefaultCatalog())
+	assert.Same(t, c, SetDefaultCatalog(nil))
+	assert.Nil(t, DefaultCatalog())
+}
+
+func TestErr_Error_CodeName(t *testing.T) {
+	e := &Err{Value: errors.New("not found"), Code: testCodeUserNotFound}
+	assert.Equal(t, "value=not found, code=1001", e.Error())
+
+	setDefaultCatalog(t, newTestCatalog())
+	assert.Equal(t, "value=not found, code=1001, code_name=USER_NOT_FOUND", e.Error())
+
+	e.Code = 404
+	assert.Equal(t, "value=not found, code=404", e.Error())
+}
+
+func TestErr_MarshalJSON_CodeName(t *testing.T) {
+	e := &Err{Value: errors.New("not found"), Code: testCodeUserNotFound}
+
+	result, err := e.JSON()
+	assert.NoError(t, err)
+	assert.NotContains(t, string(result), `"code_name"`)
+
+	setDefaultCatalog(t, newTestCatalog())
+
+	result, err = e.JSON()
+	assert.NoError(t, err)
+	assert.Contains(t, string(result), `"code_name":"USER_NOT_FOUND"`)
+
+	var decoded Err
+	assert.NoError(t, json.Unmarshal(result, &decoded))
+	assert.Equal(t, testCodeUserNotFound, decoded.Code)
+}
+
+func TestErr_Format_CodeName(t *testing.T) {
+	setDefaultCatalog(t, newTestCatalog())
+
+	e := &Err{Value: errors.New("not found"), Code: testCodeUserNotFound}
+	assert.Contains(t, fmt.Sprintf("%+v", e), "\n    code: 1001 (USER_NOT_FOUND)")
+}
+
+func TestErr_LogValue_CodeName(t *testing.T) {
+	setDefaultCatalog(t, newTestCatalog())
+
+	e := &Err{Value: errors.New("not found"), Code: testCodeUserNotFound}
+	assert.Contains(t, e.LogValue().Group(), slog.String("code_name", "USER_NOT_FOUND"))
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/fabienbellanger/xerr"
+)
+
+// goTemplate is the template of the generated Go file.
+var goTemplate = template.Must(template.New("go").Funcs(template.FuncMap{
+	"quote":    strconv.Quote,
+	"category": categoryIdent,
+	"severity": severityIdent,
+	"comment":  comment,
+}).Parse(`// Code generated by xerrgen from {{ .Source }}. DO NOT EDIT.
+
+package {{ .Spec.Package }}
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/fabienbellanger/xerr"
+)
+
+// Code is an error code of the {{ .Spec.Package }} package.
+type Code int
+
+// Error codes.
+const (
+{{- range .Spec.Errors }}
+	Code{{ .Name }} Code = {{ .Code }}
+{{- end }}
+)
+
+// Sentinel errors, used as the Value of the errors with the matching code.
+var (
+{{- range .Spec.Errors }}
+	Err{{ .Name }} = errors.New({{ quote .Value }})
+{{- end }}
+)
+
+// String returns the symbolic name of the code.
+func (c Code) String() string {
+	switch c {
+{{- range .Spec.Errors }}
+	case Code{{ .Name }}:
+		return {{ quote .Symbol }}
+{{- end }}
+	}
+	return "Code(" + strconv.Itoa(int(c)) + ")"
+}
+
+// Catalog declares the error codes of the {{ .Spec.Package }} package.
+var Catalog = xerr.NewCatalog().MustDefine(
+{{- range .Spec.Errors }}
+	xerr.CodeInfo{
+		Code:       int(Code{{ .Name }}),
+		Name:       {{ quote .Symbol }},
+		Message:    {{ quote .Message }},
+		HTTPStatus: {{ .HTTPStatus }},
+		Category:   xerr.{{ category .Category }},
+		Severity:   xerr.{{ severity .Severity }},
+{{- with .Retryable }}
+		Retryable:  new({{ . }}),
+{{- end }}
+	},
+{{- end }}
+)
+{{ range .Spec.Errors }}
+// New{{ .Name }} creates a new *xerr.Err of the code Code{{ .Name }}.
+{{- with .Description }}
+//
+{{ comment . }}
+{{- end }}
+func New{{ .Name }}(opts ...xerr.Option) *xerr.Err {
+	return Catalog.New(int(Code{{ .Name }}), Err{{ .Name }}, append([]xerr.Option{xerr.WithSkip(2)}, opts...)...)
+}
+{{ end -}}
+`))
+
+// markdownTemplate is the template of the generated markdown reference.
+var markdownTemplate = template.Must(template.New("markdown").Funcs(template.FuncMap{
+	"status":    status,
+	"retryable": retryable,
+	"cell":      cell,
+	"upper":     strings.ToUpper,
+	"lower":     strings.ToLower,
+}).Parse(`<!-- Code generated by xerrgen from {{ .Source }}. DO NOT EDIT. -->
+
+# Errors of the ` + "`{{ .Spec.Package }}`" + ` package
+
+| Code | Name | HTTP status | Category | Severity | Retryable | Message | Description |
+| ---- | ---- | ----------- | -------- | -------- | --------- | ------- | ----------- |
+{{- range .Spec.Errors }}
+| {{ .Code }} | ` + "`{{ .Symbol }}`" + ` | {{ status . }} | {{ with .Category }}{{ upper . }}{{ else }}-{{ end }} | {{ with .Severity }}{{ lower . }}{{ else }}-{{ end }} | {{ retryable . }} | {{ cell .Message }} | {{ cell .Description }} |
+{{- end }}
+`))
+
+// templateData is the data of the templates.
+type templateData struct {
+	Source string
+	Spec   Spec
+}
+
+// GenerateGo returns the formatted Go source of the catalog described by spec,
+// read from the source file.
+func GenerateGo(spec Spec, source string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := goTemplate.Execute(&buf, templateData{Source: source, Spec: spec}); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// GenerateMarkdown returns the markdown reference table of the catalog
+// described by spec, read from the source file.
+func GenerateMarkdown(spec Spec, source string) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := markdownTemplate.Execute(&buf, templateData{Source: source, Spec: spec}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// comment formats text as a Go comment.
+func comment(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("// "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// status returns the HTTP status of the error code described by e, e.g.
+// "404 Not Found".
+func status(e ErrorSpec) string {
+	category, _ := parseCategory(e.Category)
+	code := xerr.CodeInfo{HTTPStatus: e.HTTPStatus, Category: category}.Status()
+
+	return fmt.Sprintf("%d %s", code, http.StatusText(code))
+}
+
+// retryable returns whether the error code described by e is retryable: "yes",
+// "no", or "-" if it is unspecified.
+func retryable(e ErrorSpec) string {
+	switch {
+	case e.Retryable == nil:
+		return "-"
+	case *e.Retryable:
+		return "yes"
+	default:
+		return "no"
+	}
+}
+
+// cell escapes text for a markdown table cell.
+func cell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"flag"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// readSpec parses the spec in testdata.
+func readSpec(t *testing.T, name string) Spec {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+
+	spec, err := ParseSpec(data, isJSON(name))
+	require.NoError(t, err)
+
+	return spec
+}
+
+// assertGolden compares got with the golden file in testdata, updating it
+// with the -update flag.
+func assertGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name)
+	if *update {
+		require.NoError(t, os.WriteFile(path, got, 0o644))
+	}
+
+	want, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, string(want), string(got))
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of GenerateGo
+//
+// ----------------------------------------------------------------------------
+
+func TestGenerateGo(t *testing.T) {
+	got, err := GenerateGo(readSpec(t, "users.yaml"), "users.yaml")
+	require.NoError(t, err)
+
+	assertGolden(t, "users.go.golden", got)
+}
+
+func TestGenerateGo_JSON(t *testing.T) {
+	yamlCode, err := GenerateGo(readSpec(t, "users.yaml"), "users.yaml")
+	require.NoError(t, err)
+	jsonCode, err := GenerateGo(readSpec(t, "users.json"), "users.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, string(yamlCode), string(jsonCode))
+}
+
+func TestGenerateGo_ValidSource(t *testing.T) {
+	got, err := GenerateGo(readSpec(t, "users.yaml"), "users.yaml")
+	require.NoError(t, err)
+
+	file, err := parser.ParseFile(token.NewFileSet(), "users_gen.go", got, parser.ParseComments)
+	require.NoError(t, err)
+	assert.Equal(t, "users", file.Name.Name)
+	assert.True(t, file.Scope.Lookup("NewUserNotFound") != nil)
+	assert.True(t, file.Scope.Lookup("ErrEmailTaken") != nil)
+	assert.True(t, file.Scope.Lookup("CodeHTTPUpstreamTimeout") != nil)
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of GenerateMarkdown
+//
+// ----------------------------------------------------------------------------
+
+func TestGenerateMarkdown(t *testing.T) {
+	got, err := GenerateMarkdown(readSpec(t, "users.yaml"), "users.yaml")
+	require.NoError(t, err)
+
+	assertGolden(t, "users.md.golden", got)
+}
@@ -0,0 +1,112 @@
+// Command xerrgen generates a typed error catalog from a YAML or JSON spec.
+//
+// For each error of the spec, it generates a Code constant, a sentinel error,
+// a constructor returning an *xerr.Err and the matching entry of an
+// [xerr.Catalog]. It can also generate a markdown reference table of the
+// error codes.
+//
+// Usage:
+//
+//	xerrgen -spec errors.yaml [-out errors_gen.go] [-doc ERRORS.md] [-package name]
+//
+// It is typically run with go:generate:
+//
+//	//go:generate go run github.com/fabienbellanger/xerr/cmd/xerrgen -spec errors.yaml -doc ERRORS.md
+//
+// A spec looks like:
+//
+//	package: users
+//	errors:
+//	  - name: UserNotFound
+//	    code: 1001
+//	    message: The user does not exist
+//	    category: NOT_FOUND
+//	    severity: warn
+//
+// The spec is read as JSON if the file has the .json extension, and as YAML
+// otherwise.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "xerrgen:", err)
+		}
+		os.Exit(2)
+	}
+}
+
+// run runs the command with the arguments args, writing the usage to stderr.
+func run(args []string, stderr io.Writer) error {
+	flags := flag.NewFlagSet("xerrgen", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	specPath := flags.String("spec", "", "path of the YAML or JSON spec (required)")
+	out := flags.String("out", "", "path of the generated Go file (default: <spec>_gen.go)")
+	doc := flags.String("doc", "", "path of the generated markdown reference (none if empty)")
+	pkg := flags.String("package", "", "package name of the generated file, overriding the spec")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *specPath == "" {
+		flags.Usage()
+		return errors.New("missing -spec flag")
+	}
+
+	data, err := os.ReadFile(*specPath)
+	if err != nil {
+		return err
+	}
+
+	spec, err := decodeSpec(data, isJSON(*specPath))
+	if err != nil {
+		return fmt.Errorf("%s: %w", *specPath, err)
+	}
+	if *pkg != "" {
+		spec.Package = *pkg
+	}
+	if err := spec.normalize(); err != nil {
+		return fmt.Errorf("%s: %w: %w", *specPath, errInvalidSpec, err)
+	}
+
+	source := filepath.Base(*specPath)
+	if *out == "" {
+		*out = strings.TrimSuffix(*specPath, filepath.Ext(*specPath)) + "_gen.go"
+	}
+
+	code, err := GenerateGo(spec, source)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out, code, 0o644); err != nil {
+		return err
+	}
+
+	if *doc != "" {
+		markdown, err := GenerateMarkdown(spec, source)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*doc, markdown, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isJSON reports whether the spec at path is in the JSON format.
+func isJSON(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------------------------------------
+//
+// Tests of run
+//
+// ----------------------------------------------------------------------------
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "users_gen.go")
+	doc := filepath.Join(dir, "ERRORS.md")
+
+	err := run([]string{"-spec", "testdata/users.yaml", "-out", out, "-doc", doc}, os.Stderr)
+	require.NoError(t, err)
+
+	code, err := os.ReadFile(out)
+	require.NoError(t, err)
+	golden, err := os.ReadFile("testdata/users.go.golden")
+	require.NoError(t, err)
+	assert.Equal(t, string(golden), string(code))
+
+	markdown, err := os.ReadFile(doc)
+	require.NoError(t, err)
+	golden, err = os.ReadFile("testdata/users.md.golden")
+	require.NoError(t, err)
+	assert.Equal(t, string(golden), string(markdown))
+}
+
+func TestRun_Package(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "gen.go")
+
+	err := run([]string{"-spec", "testdata/users.json", "-out", out, "-package", "accounts"}, os.Stderr)
+	require.NoError(t, err)
+
+	code, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Contains(t, string(code), "// Code generated by xerrgen from users.json. DO NOT EDIT.")
+	assert.Contains(t, string(code), "\npackage accounts\n")
+}
+
+func TestRun_DefaultOut(t *testing.T) {
+	dir := t.TempDir()
+	data, err := os.ReadFile("testdata/users.yaml")
+	require.NoError(t, err)
+	spec := filepath.Join(dir, "errors.yaml")
+	require.NoError(t, os.WriteFile(spec, data, 0o644))
+
+	require.NoError(t, run([]string{"-spec", spec}, os.Stderr))
+	assert.FileExists(t, filepath.Join(dir, "errors_gen.go"))
+}
+
+func TestRun_Errors(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "gen.go")
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "missing spec flag", args: []string{"-out", out}},
+		{name: "unknown flag", args: []string{"-unknown"}},
+		{name: "missing spec file", args: []string{"-spec", "testdata/missing.yaml", "-out", out}},
+		{name: "invalid package", args: []string{"-spec", "testdata/users.yaml", "-out", out, "-package", "not-valid"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Error(t, run(tt.args, io.Discard))
+		})
+	}
+	assert.NoFileExists(t, out)
+}
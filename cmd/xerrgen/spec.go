@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"strings"
+	"unicode"
+
+	"github.com/fabienbellanger/xerr"
+	"gopkg.in/yaml.v3"
+)
+
+// Spec is the specification of an error catalog.
+type Spec struct {
+	Package string      `json:"package" yaml:"package"`
+	Errors  []ErrorSpec `json:"errors" yaml:"errors"`
+}
+
+// ErrorSpec is the specification of an error code.
+type ErrorSpec struct {
+	Name        string `json:"name" yaml:"name"`               // Go name, e.g. "UserNotFound"
+	Code        int    `json:"code" yaml:"code"`               // Error code, must not be 0
+	Symbol      string `json:"symbol" yaml:"symbol"`           // Symbolic name, defaults to "USER_NOT_FOUND"
+	Value       string `json:"value" yaml:"value"`             // Sentinel message, defaults to "user not found"
+	Message     string `json:"message" yaml:"message"`         // Default message of the errors
+	Description string `json:"description" yaml:"description"` // Description for the reference documentation
+	HTTPStatus  int    `json:"http_status" yaml:"http_status"` // HTTP status, 0 to use the one of the category
+	Category    string `json:"category" yaml:"category"`       // Canonical category, e.g. "NOT_FOUND"
+	Severity    string `json:"severity" yaml:"severity"`       // Severity level, e.g. "error"
+	Retryable   *bool  `json:"retryable" yaml:"retryable"`     // Whether the failed operation is worth retrying, nil if unspecified
+}
+
+// errInvalidSpec is returned when a spec is not valid.
+var errInvalidSpec = errors.New("invalid spec")
+
+// ParseSpec parses a spec in the JSON format if isJSON is true, or in the YAML
+// format otherwise, fills the default values and validates it.
+func ParseSpec(data []byte, isJSON bool) (Spec, error) {
+	spec, err := decodeSpec(data, isJSON)
+	if err != nil {
+		return Spec{}, err
+	}
+
+	if err := spec.normalize(); err != nil {
+		return Spec{}, fmt.Errorf("%w: %w", errInvalidSpec, err)
+	}
+	return spec, nil
+}
+
+// decodeSpec decodes a spec without validating it, rejecting unknown fields.
+func decodeSpec(data []byte, isJSON bool) (Spec, error) {
+	var (
+		spec Spec
+		err  error
+	)
+	if isJSON {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&spec)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&spec)
+	}
+
+	if err != nil {
+		return Spec{}, fmt.Errorf("%w: %w", errInvalidSpec, err)
+	}
+	return spec, nil
+}
+
+// normalize fills the default values of the spec and validates it.
+func (s *Spec) normalize() error {
+	if !token.IsIdentifier(s.Package) {
+		return fmt.Errorf("package %q is not a valid identifier", s.Package)
+	}
+	if len(s.Errors) == 0 {
+		return errors.New("no error declared")
+	}
+
+	names := make(map[string]bool)
+	codes := make(map[int]bool)
+	symbols := make(map[string]bool)
+
+	for i := range s.Errors {
+		e := &s.Errors[i]
+
+		if !token.IsIdentifier(e.Name) || !token.IsExported(e.Name) {
+			return fmt.Errorf("error %d: name %q is not an exported identifier", i, e.Name)
+		}
+		if e.Code == 0 {
+			return fmt.Errorf("error %s: code must not be 0", e.Name)
+		}
+		if e.HTTPStatus != 0 && (e.HTTPStatus < 100 || e.HTTPStatus > 599) {
+			return fmt.Errorf("error %s: invalid HTTP status %d", e.Name, e.HTTPStatus)
+		}
+		if e.Symbol == "" {
+			e.Symbol = strings.ToUpper(strings.Join(splitWords(e.Name), "_"))
+		}
+		if e.Value == "" {
+			e.Value = strings.ToLower(strings.Join(splitWords(e.Name), " "))
+		}
+		if _, ok := parseCategory(e.Category); !ok {
+			return fmt.Errorf("error %s: unknown category %q", e.Name, e.Category)
+		}
+		if _, ok := parseSeverity(e.Severity); !ok {
+			return fmt.Errorf("error %s: unknown severity %q", e.Name, e.Severity)
+		}
+
+		if names[e.Name] {
+			return fmt.Errorf("error %s: duplicate name", e.Name)
+		}
+		if codes[e.Code] {
+			return fmt.Errorf("error %s: duplicate code %d", e.Name, e.Code)
+		}
+		if symbols[e.Symbol] {
+			return fmt.Errorf("error %s: duplicate symbol %q", e.Name, e.Symbol)
+		}
+		names[e.Name], codes[e.Code], symbols[e.Symbol] = true, true, true
+	}
+
+	return nil
+}
+
+// splitWords splits a Go identifier into its words, keeping acronyms
+// together, e.g. "HTTPTimeoutError" gives "HTTP", "Timeout" and "Error".
+func splitWords(ident string) []string {
+	runes := []rune(ident)
+
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		lowerToUpper := unicode.IsLower(prev) && unicode.IsUpper(cur)
+		acronymEnd := unicode.IsUpper(prev) && unicode.IsUpper(cur) && unicode.IsLower(next)
+		digit := unicode.IsDigit(prev) != unicode.IsDigit(cur)
+
+		if lowerToUpper || acronymEnd || digit {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+
+	return append(words, string(runes[start:]))
+}
+
+// parseCategory returns the category with the canonical name, e.g.
+// [xerr.CategoryNotFound] for "NOT_FOUND". The name is case-insensitive and an
+// empty name is the unspecified category.
+func parseCategory(name string) (xerr.Category, bool) {
+	if name == "" {
+		return xerr.CategoryUnspecified, true
+	}
+
+	for c := xerr.CategoryUnspecified; c <= xerr.CategoryUnauthenticated; c++ {
+		if c.String() == strings.ToUpper(name) {
+			return c, true
+		}
+	}
+	return xerr.CategoryUnspecified, false
+}
+
+// parseSeverity returns the severity level with the name, e.g.
+// [xerr.SeverityWarn] for "warn". The name is case-insensitive and an empty
+// name is the unspecified severity.
+func parseSeverity(name string) (xerr.Severity, bool) {
+	if name == "" {
+		return xerr.SeverityUnspecified, true
+	}
+
+	for s := xerr.SeverityUnspecified; s <= xerr.SeverityCritical; s++ {
+		if s.String() == strings.ToLower(name) {
+			return s, true
+		}
+	}
+	return xerr.SeverityUnspecified, false
+}
+
+// categoryIdent returns the name of the xerr constant of the category with
+// the canonical name, e.g. "CategoryNotFound" for "NOT_FOUND".
+func categoryIdent(name string) string {
+	c, _ := parseCategory(name)
+
+	var b strings.Builder
+	b.WriteString("Category")
+	for word := range strings.SplitSeq(c.String(), "_") {
+		b.WriteString(word[:1] + strings.ToLower(word[1:]))
+	}
+	return b.String()
+}
+
+// severityIdent returns the name of the xerr constant of the severity level
+// with the name, e.g. "SeverityWarn" for "warn".
+func severityIdent(name string) string {
+	s, _ := parseSeverity(name)
+	return "Severity" + strings.ToUpper(s.String()[:1]) + s.String()[1:]
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fabienbellanger/xerr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ----------------------------------------------------------------------------
+//
+// Tests of ParseSpec
+//
+// ----------------------------------------------------------------------------
+
+func TestParseSpec_Defaults(t *testing.T) {
+	spec, err := ParseSpec([]byte("package: users\nerrors:\n  - name: HTTPUpstreamTimeout\n    code: 1\n"), false)
+	require.NoError(t, err)
+
+	require.Len(t, spec.Errors, 1)
+	assert.Equal(t, "HTTP_UPSTREAM_TIMEOUT", spec.Errors[0].Symbol)
+	assert.Equal(t, "http upstream timeout", spec.Errors[0].Value)
+}
+
+func TestParseSpec_YAMLAndJSON(t *testing.T) {
+	assert.Equal(t, readSpec(t, "users.yaml"), readSpec(t, "users.json"))
+}
+
+func TestParseSpec_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   string
+		isJSON bool
+	}{
+		{name: "malformed YAML", data: "package: [users"},
+		{name: "malformed JSON", data: `{"package": "users"`, isJSON: true},
+		{name: "unknown YAML field", data: "package: users\nerrors:\n  - name: A\n    code: 1\n    unknown: 1\n"},
+		{name: "unknown JSON field", data: `{"package": "users", "errors": [{"name": "A", "code": 1, "unknown": 1}]}`, isJSON: true},
+		{name: "invalid package", data: "package: not-valid\nerrors:\n  - name: A\n    code: 1\n"},
+		{name: "no error", data: "package: users\n"},
+		{name: "unexported name", data: "package: users\nerrors:\n  - name: notFound\n    code: 1\n"},
+		{name: "zero code", data: "package: users\nerrors:\n  - name: A\n"},
+		{name: "invalid HTTP status", data: "package: users\nerrors:\n  - name: A\n    code: 1\n    http_status: 600\n"},
+		{name: "unknown category", data: "package: users\nerrors:\n  - name: A\n    code: 1\n    category: MISSING\n"},
+		{name: "unknown severity", data: "package: users\nerrors:\n  - name: A\n    code: 1\n    severity: fatal\n"},
+		{name: "duplicate name", data: "package: users\nerrors:\n  - name: A\n    code: 1\n  - name: A\n    code: 2\n"},
+		{name: "duplicate code", data: "package: users\nerrors:\n  - name: A\n    code: 1\n  - name: B\n    code: 1\n"},
+		{name: "duplicate symbol", data: "package: users\nerrors:\n  - name: A\n    code: 1\n  - name: B\n    code: 2\n    symbol: A\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSpec([]byte(tt.data), tt.isJSON)
+			assert.True(t, errors.Is(err, errInvalidSpec), "got %v", err)
+		})
+	}
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of the helpers
+//
+// ----------------------------------------------------------------------------
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		ident string
+		want  []string
+	}{
+		{ident: "A", want: []string{"A"}},
+		{ident: "UserNotFound", want: []string{"User", "Not", "Found"}},
+		{ident: "HTTPTimeoutError", want: []string{"HTTP", "Timeout", "Error"}},
+		{ident: "InvalidUserID", want: []string{"Invalid", "User", "ID"}},
+		{ident: "OAuth2Failed", want: []string{"O", "Auth", "2", "Failed"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ident, func(t *testing.T) {
+			assert.Equal(t, tt.want, splitWords(tt.ident))
+		})
+	}
+}
+
+func TestParseCategory(t *testing.T) {
+	c, ok := parseCategory("not_found")
+	assert.True(t, ok)
+	assert.Equal(t, xerr.CategoryNotFound, c)
+
+	c, ok = parseCategory("")
+	assert.True(t, ok)
+	assert.Equal(t, xerr.CategoryUnspecified, c)
+
+	_, ok = parseCategory("MISSING")
+	assert.False(t, ok)
+}
+
+func TestParseSeverity(t *testing.T) {
+	s, ok := parseSeverity("WARN")
+	assert.True(t, ok)
+	assert.Equal(t, xerr.SeverityWarn, s)
+
+	s, ok = parseSeverity("")
+	assert.True(t, ok)
+	assert.Equal(t, xerr.SeverityUnspecified, s)
+
+	_, ok = parseSeverity("fatal")
+	assert.False(t, ok)
+}
+
+func TestCategoryIdent(t *testing.T) {
+	assert.Equal(t, "CategoryNotFound", categoryIdent("NOT_FOUND"))
+	assert.Equal(t, "CategoryDeadlineExceeded", categoryIdent("deadline_exceeded"))
+	assert.Equal(t, "CategoryUnspecified", categoryIdent(""))
+}
+
+func TestSeverityIdent(t *testing.T) {
+	assert.Equal(t, "SeverityWarn", severityIdent("warn"))
+	assert.Equal(t, "SeverityCritical", severityIdent("CRITICAL"))
+	assert.Equal(t, "SeverityUnspecified", severityIdent(""))
+}
@@ -0,0 +1,50 @@
+package xerr
+
+// NewWith is like [New] but with typed details, so the compiler checks the
+// type of the details attached to the error. Returns nil if value is nil.
+//
+// The optional skip parameter works the same as in [New].
+//
+// Example:
+//
+//	err := xerr.NewWith(ErrInvalid, "invalid input", ValidationDetails{Field: "email"}, 400, nil)
+func NewWith[T any](value error, msg string, details T, code int, prev *Err, skip ...int) *Err {
+	return Make(value,
+		WithMsg(msg),
+		WithDetails(details),
+		WithCode(code),
+		WithPrev(prev),
+		WithSkip(callerSkip(skip)+1),
+	)
+}
+
+// DetailsAs returns the Details of the first Err of the Prev chain, starting
+// with e, whose Details is of type T.
+//
+// Example:
+//
+//	if v, ok := xerr.DetailsAs[ValidationDetails](err); ok {
+//		fmt.Println("Invalid field:", v.Field)
+//	}
+func DetailsAs[T any](e *Err) (T, bool) {
+	for link := range e.All() {
+		if details, ok := link.Details.(T); ok {
+			return details, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// AllDetails returns the Details of every Err of the Prev chain, starting with
+// e, whose Details is of type T. Returns nil if there is none.
+func AllDetails[T any](e *Err) []T {
+	var all []T
+	for link := range e.All() {
+		if details, ok := link.Details.(T); ok {
+			all = append(all, details)
+		}
+	}
+	return all
+}
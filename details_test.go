@@ -0,0 +1,227 @@
+package xerr
+
+import (
+	"encoding/json"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// validationDetails is a details type used to test typed details.
+type validationDetails struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// registerDetails registers the details type T under name for the duration of
+// the test.
+func registerDetails[T any](t *testing.T, name string) {
+	t.Helper()
+
+	assert.NoError(t, RegisterDetails[T](name))
+	t.Cleanup(func() {
+		detailTypes.Lock()
+		defer detailTypes.Unlock()
+
+		delete(detailTypes.byType, detailTypes.byName[name])
+		delete(detailTypes.byName, name)
+	})
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of RegisterDetails()
+//
+// ----------------------------------------------------------------------------
+
+func TestRegisterDetails_Duplicate(t *testing.T) {
+	registerDetails[validationDetails](t, "test.validation")
+
+	assert.ErrorIs(t, RegisterDetails[validationDetails]("test.other"), ErrAlreadyRegistered)
+	assert.ErrorIs(t, RegisterDetails[map[string]int]("test.validation"), ErrAlreadyRegistered)
+}
+
+func TestRegisterDetails_Invalid(t *testing.T) {
+	assert.ErrorIs(t, RegisterDetails[validationDetails](""), ErrInvalidRegistration)
+	assert.ErrorIs(t, RegisterDetails[error]("test.error"), ErrInvalidRegistration)
+}
+
+func TestMustRegisterDetails_Panics(t *testing.T) {
+	registerDetails[validationDetails](t, "test.validation")
+
+	assert.Panics(t, func() { MustRegisterDetails[validationDetails]("test.other") })
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of JSON encoding of registered details
+//
+// ----------------------------------------------------------------------------
+
+func TestRegisterDetails_MarshalJSON(t *testing.T) {
+	registerDetails[validationDetails](t, "test.validation")
+
+	details := validationDetails{Field: "email", Reason: "invalid"}
+	result, err := New(errors.New("test"), "", details, 0, nil).JSON()
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(result), `"details":{"field":"email","reason":"invalid"},"details_type":"test.validation"`)
+}
+
+func TestRegisterDetails_MarshalJSON_Unregistered(t *testing.T) {
+	details := validationDetails{Field: "email", Reason: "invalid"}
+	result, err := New(errors.New("test"), "", details, 0, nil).JSON()
+
+	assert.NoError(t, err)
+	assert.NotContains(t, string(result), `"details_type"`)
+}
+
+func TestRegisterDetails_UnmarshalJSON(t *testing.T) {
+	registerDetails[validationDetails](t, "test.validation")
+	registerDetails[*validationDetails](t, "test.validation_ptr")
+
+	details := validationDetails{Field: "email", Reason: "invalid"}
+	root := New(errors.New("root"), "", &details, 0, nil)
+	e := New(errors.New("test"), "", details, 0, root)
+	data, err := e.JSON()
+	assert.NoError(t, err)
+
+	var decoded Err
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+
+	assert.Equal(t, details, decoded.Details)
+	assert.Equal(t, &details, decoded.Prev.Details)
+}
+
+func TestRegisterDetails_UnmarshalJSON_Unregistered(t *testing.T) {
+	var decoded Err
+	err := json.Unmarshal([]byte(`{"value":"test","details":{"field":"email"},"details_type":"test.unknown"}`), &decoded)
+
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"field": "email"}, decoded.Details)
+}
+
+func TestRegisterDetails_UnmarshalJSON_Invalid(t *testing.T) {
+	registerDetails[validationDetails](t, "test.validation")
+
+	var decoded Err
+	err := json.Unmarshal([]byte(`{"value":"test","details":{"field":1},"details_type":"test.validation"}`), &decoded)
+	assert.Error(t, err)
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of DetailsAs()
+//
+// ----------------------------------------------------------------------------
+
+func TestDetailsAs(t *testing.T) {
+	details := validationDetails{Field: "email"}
+	e := New(errors.New("test"), "", details, 0, nil)
+
+	got, ok := DetailsAs[validationDetails](e)
+	assert.True(t, ok)
+	assert.Equal(t, details, got)
+}
+
+func TestDetailsAs_WalksChain(t *testing.T) {
+	root := New(errors.New("root"), "", validationDetails{Field: "root"}, 0, nil)
+	middle := New(errors.New("middle"), "", validationDetails{Field: "middle"}, 0, root)
+	e := New(errors.New("test"), "", map[string]int{"id": 1}, 0, middle)
+
+	got, ok := DetailsAs[validationDetails](e)
+	assert.True(t, ok)
+	assert.Equal(t, "middle", got.Field)
+
+	ids, ok := DetailsAs[map[string]int](e)
+	assert.True(t, ok)
+	assert.Equal(t, 1, ids["id"])
+}
+
+func TestDetailsAs_NotFound(t *testing.T) {
+	e := New(errors.New("test"), "", validationDetails{Field: "email"}, 0, nil)
+
+	got, ok := DetailsAs[*validationDetails](e)
+	assert.False(t, ok)
+	assert.Nil(t, got)
+
+	var nilErr *Err
+	_, ok = DetailsAs[validationDetails](nilErr)
+	assert.False(t, ok)
+}
+
+func TestDetailsAs_AfterJSONRoundTrip(t *testing.T) {
+	registerDetails[validationDetails](t, "test.validation")
+
+	root := New(errors.New("root"), "", validationDetails{Field: "email"}, 0, nil)
+	data, err := New(errors.New("test"), "", nil, 0, root).JSON()
+	assert.NoError(t, err)
+
+	var decoded Err
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+
+	got, ok := DetailsAs[validationDetails](&decoded)
+	assert.True(t, ok)
+	assert.Equal(t, "email", got.Field)
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of AllDetails()
+//
+// ----------------------------------------------------------------------------
+
+func TestAllDetails(t *testing.T) {
+	root := New(errors.New("root"), "", validationDetails{Field: "root"}, 0, nil)
+	middle := New(errors.New("middle"), "", map[string]int{"id": 1}, 0, root)
+	e := New(errors.New("test"), "", validationDetails{Field: "outer"}, 0, middle)
+
+	got := AllDetails[validationDetails](e)
+	assert.Equal(t, []validationDetails{{Field: "outer"}, {Field: "root"}}, got)
+}
+
+func TestAllDetails_NotFound(t *testing.T) {
+	e := New(errors.New("test"), "", validationDetails{Field: "email"}, 0, nil)
+	assert.Nil(t, AllDetails[string](e))
+
+	var nilErr *Err
+	assert.Nil(t, AllDetails[validationDetails](nilErr))
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of NewWith()
+//
+// ----------------------------------------------------------------------------
+
+func TestNewWith(t *testing.T) {
+	prev := NewSimple(errors.New("root"), "root cause", nil)
+	details := validationDetails{Field: "email", Reason: "invalid"}
+
+	_, _, wantLine, _ := runtime.Caller(0)
+	wantLine += 2
+	e := NewWith(errors.New("test"), "My error message", details, 400, prev)
+
+	assert.Equal(t, errors.New("test"), e.Value)
+	assert.Equal(t, "My error message", e.Msg)
+	assert.Equal(t, details, e.Details)
+	assert.Equal(t, 400, e.Code)
+	assert.Equal(t, prev, e.Prev)
+	assert.NotSame(t, prev, e.Prev)
+	assert.True(t, strings.Contains(e.File, "details_test.go"))
+	assert.Equal(t, wantLine, e.Line)
+}
+
+func TestNewWith_ReturnsNilOnNilValue(t *testing.T) {
+	assert.Nil(t, NewWith(nil, "My error message", validationDetails{}, 0, nil))
+}
+
+func TestNewWith_WithSkip(t *testing.T) {
+	e := NewWith(errors.New("test"), "My error message", 42, 0, nil, 0)
+
+	// skip=0 → runtime.Caller(0) points inside details.go, not at the call site
+	assert.True(t, strings.Contains(e.File, "details.go"))
+}
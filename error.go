@@ -10,23 +10,106 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
-	"runtime/debug"
+	"slices"
+	"strings"
 	"time"
 )
 
-// Err wraps an error with structured context: an optional code, human-readable
-// message, arbitrary details, call-site location (File, Line), timestamp, stack
-// trace, and a Prev pointer that forms a linked chain of errors.
+// Err wraps an error with structured context: an optional code and severity,
+// human-readable message, public message shown to end users, arbitrary
+// details, ordered key-value Tags (see [Err.With]), call-site location (File,
+// Line), timestamp, stack trace, and a Prev pointer that forms a linked chain
+// of errors.
+//
+// Retryable, Temporary and RetryAfter classify whether the failed operation is
+// worth retrying; a nil Retryable or Temporary inherits the classification of
+// the Prev chain, see [Retryable] and [Temporary].
+//
+// Each error of the chain may also have several Causes, e.g. the failures of
+// parallel calls, each one with its own chain, so that the errors form a tree.
 type Err struct {
-	Value      error  `json:"value"`
-	Code       int    `json:"code,omitzero"`
-	Msg        string `json:"msg"`
-	Details    any    `json:"details"`
-	File       string `json:"file"`
-	Line       int    `json:"line"`
-	Timestamp  int64  `json:"timestamp"`
-	Prev       *Err   `json:"prev"`
-	StackTrace []byte `json:"stack_trace,omitempty"`
+	Value      error         `json:"value"`
+	Code       int           `json:"code,omitzero"`
+	Severity   Severity      `json:"severity,omitzero"`
+	Msg        string        `json:"msg"`
+	PublicMsg  string        `json:"public_msg,omitempty"`
+	Details    any           `json:"details"`
+	Tags       Fields        `json:"tags,omitempty"`
+	File       string        `json:"file"`
+	Line       int           `json:"line"`
+	Timestamp  int64         `json:"timestamp"`
+	Prev       *Err          `json:"prev"`
+	StackTrace *Stack        `json:"stack_trace,omitempty"`
+	Causes     []*Err        `json:"causes,omitempty"`
+	Retryable  *bool         `json:"retryable,omitempty"`
+	Temporary  *bool         `json:"temporary,omitempty"`
+	RetryAfter time.Duration `json:"retry_after,omitzero"`
+}
+
+// Make creates a new *Err with the provided error value, configured by the
+// given options. Returns nil if value is nil.
+//
+// The call site (File, Line) is the caller of Make unless overridden with
+// [WithSkip]. The previous error set with [WithPrev] and the causes set with
+// [WithCauses] are cloned to avoid mutation. The stack trace is captured
+// according to the [StackPolicy] set with [WithStackPolicy], or the
+// package-level one set with [SetStackPolicy].
+//
+// Example:
+//
+//	var myError = errors.New("my error")
+//	err := Make(myError, WithMsg("My error message"), WithCode(404))
+func Make(value error, opts ...Option) *Err {
+	return build(value, newOptions(opts))
+}
+
+// build creates a new *Err from the provided error value and options. It must
+// be called directly by the exported constructors, so that the skip option is
+// relative to them.
+func build(value error, o options) *Err {
+	if value == nil {
+		return nil
+	}
+
+	_, file, line, _ := runtime.Caller(o.skip + 1)
+
+	timestamp := o.timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+
+	prev := o.prev
+	if !o.sharePrev {
+		prev = prev.Clone()
+	}
+
+	e := &Err{
+		Value:      value,
+		Code:       o.code,
+		Severity:   o.severity,
+		Msg:        o.msg,
+		PublicMsg:  o.publicMsg,
+		Details:    o.details,
+		Tags:       o.tags,
+		File:       file,
+		Line:       line,
+		Timestamp:  timestamp.UnixMicro(),
+		Prev:       prev,
+		Causes:     cloneCauses(o.causes, &path{}),
+		Retryable:  o.retryable,
+		Temporary:  o.temporary,
+		RetryAfter: o.retryAfter,
+	}
+
+	policy := o.stack
+	if policy == nil {
+		policy = currentStackPolicy()
+	}
+	if policy(e) {
+		e.StackTrace = newStack(o.skip + 1)
+	}
+
+	return e
 }
 
 // New creates a new *Err with the provided error value, message, details, code,
@@ -47,28 +130,13 @@ type Err struct {
 //	details := Person{Name: "John", Age: 30}
 //	err := New(myError, "My error message", details, 0, nil)
 func New(value error, msg string, details any, code int, prev *Err, skip ...int) *Err {
-	if value == nil {
-		return nil
-	}
-
-	callerSkip := 1
-	if len(skip) == 1 {
-		callerSkip = skip[0]
-	}
-	_, file, line, _ := runtime.Caller(callerSkip)
-	stack := debug.Stack()
-
-	return &Err{
-		Value:      value,
-		Code:       code,
-		Msg:        msg,
-		Details:    details,
-		File:       file,
-		Line:       line,
-		Timestamp:  time.Now().UnixMicro(),
-		Prev:       prev.Clone(),
-		StackTrace: stack,
-	}
+	return Make(value,
+		WithMsg(msg),
+		WithDetails(details),
+		WithCode(code),
+		WithPrev(prev),
+		WithSkip(callerSkip(skip)+1),
+	)
 }
 
 // NewSimple creates a new *Err with only a value, message, and optional prev
@@ -76,21 +144,11 @@ func New(value error, msg string, details any, code int, prev *Err, skip ...int)
 //
 // The optional skip parameter works the same as in [New].
 func NewSimple(value error, msg string, prev *Err, skip ...int) *Err {
-	e := New(value, msg, nil, 0, prev)
-	if e == nil {
-		return nil
-	}
-
-	callerSkip := 1
-	if len(skip) == 1 {
-		callerSkip = skip[0]
-	}
-	_, file, line, _ := runtime.Caller(callerSkip)
-
-	e.File = file
-	e.Line = line
-
-	return e
+	return Make(value,
+		WithMsg(msg),
+		WithPrev(prev),
+		WithSkip(callerSkip(skip)+1),
+	)
 }
 
 // Wrap creates a new *Err with value, msg, details, and code, chaining the
@@ -98,45 +156,77 @@ func NewSimple(value error, msg string, prev *Err, skip ...int) *Err {
 //
 // The optional skip parameter works the same as in [New].
 func (e *Err) Wrap(value error, msg string, details any, code int, skip ...int) *Err {
-	err := New(value, msg, details, code, e)
+	return Make(value,
+		WithMsg(msg),
+		WithDetails(details),
+		WithCode(code),
+		WithPrev(e),
+		WithSkip(callerSkip(skip)+1),
+	)
+}
 
-	callerSkip := 1
+// callerSkip returns the optional skip parameter of the positional
+// constructors, defaulting to 1.
+func callerSkip(skip []int) int {
 	if len(skip) == 1 {
-		callerSkip = skip[0]
+		return skip[0]
 	}
-	_, file, line, _ := runtime.Caller(callerSkip)
-
-	err.File = file
-	err.Line = line
-
-	return err
+	return 1
 }
 
 // Clone creates a deep copy of the Err struct.
 //
-// It recursively clones the Prev field to ensure that the entire error chain
-// is duplicated. Returns nil if called on a nil pointer.
+// It clones the Prev and Causes fields to ensure that the entire error tree is
+// duplicated. A cycle is cut: the error whose Prev or cause leads back to one
+// of its ancestors is cloned without it. The StackTrace is immutable and
+// therefore shared with the clone. Returns nil if called on a nil pointer.
 func (e *Err) Clone() *Err {
-	if e == nil {
-		return nil
-	}
+	return e.clone(&path{})
+}
 
-	var clonedPrev *Err
-	if e.Prev != nil {
-		clonedPrev = e.Prev.Clone()
+// clone implements [Err.Clone], cloning the chain iteratively so that long
+// chains do not grow the stack.
+func (e *Err) clone(p *path) *Err {
+	mark := p.n
+
+	var head *Err
+	next := &head
+	for link := e; link != nil && p.push(link); link = link.Prev {
+		cloned := &Err{
+			Value:      link.Value,
+			Code:       link.Code,
+			Severity:   link.Severity,
+			Msg:        link.Msg,
+			PublicMsg:  link.PublicMsg,
+			Details:    link.Details,
+			Tags:       slices.Clone(link.Tags),
+			File:       link.File,
+			Line:       link.Line,
+			Timestamp:  link.Timestamp,
+			StackTrace: link.StackTrace,
+			Causes:     cloneCauses(link.Causes, p),
+			Retryable:  cloneFlag(link.Retryable),
+			Temporary:  cloneFlag(link.Temporary),
+			RetryAfter: link.RetryAfter,
+		}
+		*next = cloned
+		next = &cloned.Prev
 	}
 
-	return &Err{
-		Value:      e.Value,
-		Code:       e.Code,
-		Msg:        e.Msg,
-		Details:    e.Details,
-		File:       e.File,
-		Line:       e.Line,
-		Timestamp:  e.Timestamp,
-		Prev:       clonedPrev,
-		StackTrace: e.StackTrace,
+	p.truncate(mark)
+	return head
+}
+
+// cloneCauses returns a copy of causes with each non-nil cause cloned, or nil
+// if there is none.
+func cloneCauses(causes []*Err, p *path) []*Err {
+	var cloned []*Err
+	for _, cause := range causes {
+		if cause := cause.clone(p); cause != nil {
+			cloned = append(cloned, cause)
+		}
 	}
+	return cloned
 }
 
 // Empty returns nil, representing the absence of an error.
@@ -157,41 +247,161 @@ func (e *Err) IsError() bool {
 
 // Error implements the error interface, returning a human-readable string with
 // all non-zero fields formatted as key=value pairs (e.g. "value=…, code=…").
+// The symbolic name of the code is emitted as code_name if it is declared in
+// the catalog set with [SetDefaultCatalog]. The errors of the chain beyond the
+// depth set with [SetMaxDepth] are replaced by "… N more", and an error
+// leading back to one of its ancestors by "… cycle". Msg, Details and Tags
+// are redacted for [OutputLog] by the [Redactor] set with [SetRedactor].
 func (e *Err) Error() string {
 	if e.IsEmpty() {
 		return ""
 	}
 
-	result := fmt.Sprintf("value=%v", e.Value)
+	var b strings.Builder
+	e.writeError(&b, &path{})
+	return b.String()
+}
+
+// writeError writes the key=value pairs of each error of the chain to b, each
+// previous error being nested in "prev={…}".
+func (e *Err) writeError(b *strings.Builder, p *path) {
+	mark := p.n
+	nested := 0
 
-	if e.Code != 0 {
-		result += fmt.Sprintf(", code=%d", e.Code)
-	}
+	for depth, link := 0, e; !link.IsEmpty(); depth, link = depth+1, link.Prev {
+		if depth > 0 {
+			b.WriteString(", prev={")
+			nested++
+		}
+		if !p.push(link) {
+			b.WriteString("… cycle")
+			break
+		}
+		if truncated(depth) {
+			fmt.Fprintf(b, "… %d more", link.Depth())
+			break
+		}
 
-	if e.Msg != "" {
-		result += fmt.Sprintf(", msg=%+v", e.Msg)
-	}
+		fmt.Fprintf(b, "value=%v", link.Value)
 
-	if e.Details != nil {
-		result += fmt.Sprintf(", details=%+v", e.Details)
-	}
+		if link.Code != 0 {
+			fmt.Fprintf(b, ", code=%d", link.Code)
+		}
 
-	if e.File != "" {
-		result += fmt.Sprintf(", source=%s:%d", e.File, e.Line)
-	}
+		if name := codeName(link.Code); name != "" {
+			fmt.Fprintf(b, ", code_name=%s", name)
+		}
+
+		if link.Severity != SeverityUnspecified {
+			fmt.Fprintf(b, ", severity=%s", link.Severity)
+		}
+
+		msg, details := link.redact(OutputLog)
+
+		if msg != "" {
+			fmt.Fprintf(b, ", msg=%+v", msg)
+		}
+
+		if link.PublicMsg != "" {
+			fmt.Fprintf(b, ", public_msg=%s", link.PublicMsg)
+		}
+
+		if details != nil {
+			fmt.Fprintf(b, ", details=%+v", details)
+		}
+
+		if tags := link.redactTags(OutputLog); len(tags) > 0 {
+			b.WriteString(", tags=[")
+			writeTags(b, tags)
+			b.WriteString("]")
+		}
+
+		if link.File != "" {
+			fmt.Fprintf(b, ", source=%s:%d", link.File, link.Line)
+		}
+
+		if link.Retryable != nil {
+			fmt.Fprintf(b, ", retryable=%t", *link.Retryable)
+		}
+
+		if link.Temporary != nil {
+			fmt.Fprintf(b, ", temporary=%t", *link.Temporary)
+		}
+
+		if link.RetryAfter > 0 {
+			fmt.Fprintf(b, ", retry_after=%s", link.RetryAfter)
+		}
+
+		if link.Timestamp != 0 {
+			fmt.Fprintf(b, ", timestamp=%s", time.UnixMicro(link.Timestamp).Format(time.RFC3339Nano))
+		}
 
-	if e.Timestamp != 0 {
-		result += fmt.Sprintf(", timestamp=%s", time.UnixMicro(e.Timestamp).Format(time.RFC3339Nano))
+		if len(link.Causes) > 0 {
+			b.WriteString(", causes=[")
+			for i, cause := range link.Causes {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				b.WriteString("{")
+				if p.contains(cause) {
+					b.WriteString("… cycle")
+				} else {
+					cause.writeError(b, p)
+				}
+				b.WriteString("}")
+			}
+			b.WriteString("]")
+		}
+
+		if link.Prev != nil && link.Prev.IsEmpty() {
+			b.WriteString(", prev={}")
+		}
 	}
 
-	if e.Prev != nil {
-		result += fmt.Sprintf(", prev={%s}", e.Prev.Error())
+	b.WriteString(strings.Repeat("}", nested))
+	p.truncate(mark)
+}
+
+// Walk calls fn for each Err of the tree rooted at e, depth-first: each error
+// of the Prev chain is visited before its Causes, which are visited before
+// the next error of the chain. Walk stops as soon as fn returns false. An
+// error leading back to one of its ancestors is not visited again, so Walk
+// terminates on cyclic trees. It does nothing if called on a nil pointer.
+//
+// Example:
+//
+//	err.Walk(func(link *Err) bool {
+//		fmt.Println(link.Msg)
+//		return true
+//	})
+func (e *Err) Walk(fn func(link *Err) bool) {
+	e.walk(fn, &path{})
+}
+
+// walk implements [Err.Walk], reporting whether the whole tree was visited.
+// The errors leading back to one of their ancestors are not visited again.
+func (e *Err) walk(fn func(link *Err) bool, p *path) bool {
+	mark := p.n
+	for link := e; link != nil && p.push(link); link = link.Prev {
+		if !fn(link) {
+			return false
+		}
+		for _, cause := range link.Causes {
+			if !cause.walk(fn, p) {
+				return false
+			}
+		}
 	}
 
-	return result
+	p.truncate(mark)
+	return true
 }
 
-// Is reports whether any Value in the Prev chain matches target using [errors.Is].
+// Is reports whether any Value of the tree walked by [Err.Walk] matches target
+// using [errors.Is], or whether target is one of the Err of the tree.
+//
+// Is gives the same result as [errors.Is] called on the receiver, which walks
+// the same tree through [Err.Unwrap].
 //
 // Example:
 //
@@ -204,52 +414,197 @@ func (e *Err) Is(err error) bool {
 	if e == nil {
 		return false
 	}
-	if errors.Is(e.Value, err) {
-		return true
+
+	found := false
+	e.Walk(func(link *Err) bool {
+		found = error(link) == err || errors.Is(link.Value, err)
+		return !found
+	})
+	return found
+}
+
+// As finds the first Value of the tree walked by [Err.Walk] that matches
+// target using [errors.As], and if one is found, sets target to that error
+// value and returns true. Otherwise, it returns false.
+//
+// Like [errors.As], As panics if target is not a non-nil pointer to either a
+// type that implements error, or to any interface type.
+//
+// Example:
+//
+//	var pathErr *fs.PathError
+//	if err.As(&pathErr) {
+//		fmt.Println("Failed at path:", pathErr.Path)
+//	}
+func (e *Err) As(target any) bool {
+	if e == nil {
+		return false
 	}
 
-	prev := e.Prev
-	for prev != nil {
-		if errors.Is(prev.Value, err) {
+	found := false
+	e.Walk(func(link *Err) bool {
+		found = link.Value != nil && errors.As(link.Value, target)
+		return !found
+	})
+	return found
+}
+
+// Unwrap returns the Value, the Prev error and the Causes of the receiver,
+// skipping nil ones. It implements the multiple errors interface walked by
+// [errors.Is] and [errors.As], so the whole tree and the wrapped values are
+// visible to the standard library.
+//
+// The Prev error and the Causes are not returned as *Err but as errors
+// wrapping them, which match only their own error in [errors.Is] and
+// [errors.As] and unwrap the rest of the tree, so that the standard library
+// walks the tree in linear time. They format and print like the *Err they
+// wrap; use [errors.As] with a target of type *Err to get it. The errors
+// leading back to one of their ancestors are skipped, so that a cyclic tree
+// is not walked forever.
+func (e *Err) Unwrap() []error {
+	if e == nil {
+		return nil
+	}
+	return e.unwrap(!e.acyclic(), nil)
+}
+
+// unwrap implements [Err.Unwrap]. If the tree is cyclic, ancestors lists the
+// errors from the root of the walk to the parent of e, and the Prev error and
+// the Causes leading back to e or one of them are skipped.
+func (e *Err) unwrap(cyclic bool, ancestors *ancestor) []error {
+	if cyclic {
+		ancestors = &ancestor{e: e, parent: ancestors}
+	}
+
+	var errs []error
+	if e.Value != nil {
+		errs = append(errs, e.Value)
+	}
+	for i := -1; i < len(e.Causes); i++ {
+		child := e.Prev
+		if i >= 0 {
+			child = e.Causes[i]
+		}
+		if child == nil || (cyclic && ancestors.contains(child)) {
+			continue
+		}
+		errs = append(errs, &treeLink{e: child, cyclic: cyclic, ancestors: ancestors})
+	}
+	return errs
+}
+
+// acyclic reports whether no error of the tree rooted at e leads back to one
+// of its ancestors.
+func (e *Err) acyclic() bool {
+	return e.acyclicFrom(&path{})
+}
+
+// acyclicFrom implements [Err.acyclic].
+func (e *Err) acyclicFrom(p *path) bool {
+	mark := p.n
+	for link := e; link != nil; link = link.Prev {
+		if !p.push(link) {
+			return false
+		}
+		for _, cause := range link.Causes {
+			if !cause.acyclicFrom(p) {
+				return false
+			}
+		}
+	}
+
+	p.truncate(mark)
+	return true
+}
+
+// ancestor is an immutable list of the errors from the root of a walk of a
+// cyclic tree to the current error, innermost first.
+type ancestor struct {
+	e      *Err
+	parent *ancestor
+}
+
+// contains reports whether e is in the list.
+func (a *ancestor) contains(e *Err) bool {
+	for ; a != nil; a = a.parent {
+		if a.e == e {
 			return true
 		}
-		prev = prev.Prev
 	}
 	return false
 }
 
-// Unwrap returns the previous error in the chain, satisfying the interface
-// expected by [errors.Unwrap].
-func (e *Err) Unwrap() error {
-	if e == nil || e.Prev == nil {
-		return nil
+// treeLink is an error of the tree returned by [Err.Unwrap]. It is immutable,
+// so the values returned by Unwrap can be walked several times and
+// concurrently.
+type treeLink struct {
+	e         *Err
+	cyclic    bool
+	ancestors *ancestor
+}
+
+// Error implements the error interface, see [Err.Error].
+func (l *treeLink) Error() string {
+	return l.e.Error()
+}
+
+// Format implements [fmt.Formatter], see [Err.Format].
+func (l *treeLink) Format(s fmt.State, verb rune) {
+	l.e.Format(s, verb)
+}
+
+// Is reports whether target is the wrapped error. Its Value is matched by
+// [errors.Is] through [treeLink.Unwrap].
+func (l *treeLink) Is(err error) bool {
+	return error(l.e) == err
+}
+
+// As sets target to the wrapped error if target is a *Err. Its Value is
+// matched by [errors.As] through [treeLink.Unwrap].
+func (l *treeLink) As(target any) bool {
+	if p, ok := target.(**Err); ok {
+		*p = l.e
+		return true
 	}
-	return e.Prev
+	return false
+}
+
+// Unwrap returns the Value, the Prev error and the Causes of the wrapped
+// error, skipping the ones leading back to one of its ancestors.
+func (l *treeLink) Unwrap() []error {
+	return l.e.unwrap(l.cyclic, l.ancestors)
 }
 
 // FromError creates a new *Err from a plain error, capturing the caller's
 // file and line. Returns nil if err is nil.
 func FromError(err error) *Err {
-	if err == nil {
-		return nil
-	}
-	return New(err, "", nil, 0, nil)
+	return Make(err, WithSkip(2))
 }
 
-// JSON returns the JSON encoding of the Err. The stack trace is omitted
-// unless stackTrace is true. It operates on a clone to avoid mutating the
-// receiver.
+// JSON returns the JSON encoding of the Err for [OutputLog]. The stack traces
+// of the errors of the tree are omitted unless stackTrace is true. It
+// operates on a clone to avoid mutating the receiver.
 func (e *Err) JSON(stackTrace ...bool) ([]byte, error) {
+	return e.JSONFor(OutputLog, stackTrace...)
+}
+
+// JSONFor is like [Err.JSON], with the Msg, Details and Tags of each error of
+// the tree redacted for out by the [Redactor] set with [SetRedactor]. Use
+// [OutputClient] for the responses sent to clients.
+func (e *Err) JSONFor(out Output, stackTrace ...bool) ([]byte, error) {
 	if e.IsEmpty() {
 		return []byte{}, nil
 	}
 
 	clone := e.Clone()
 	if len(stackTrace) == 0 || !stackTrace[0] {
-		clone.StackTrace = nil
+		clone.Walk(func(link *Err) bool {
+			link.StackTrace = nil
+			return true
+		})
 	}
 
-	s, err := json.Marshal(clone)
+	s, err := json.Marshal(clone.toJSON(out, &path{}))
 	if err != nil {
 		return []byte{}, err
 	}
@@ -260,16 +615,7 @@ func (e *Err) JSON(stackTrace ...bool) ([]byte, error) {
 // JSONOrEmpty is like [Err.JSON] but silently returns an empty byte slice on
 // error or if the Err is empty.
 func (e *Err) JSONOrEmpty(stackTrace ...bool) []byte {
-	if e.IsEmpty() {
-		return []byte{}
-	}
-
-	clone := e.Clone()
-	if len(stackTrace) == 0 || !stackTrace[0] {
-		clone.StackTrace = nil
-	}
-
-	s, err := json.Marshal(clone)
+	s, err := e.JSON(stackTrace...)
 	if err != nil {
 		return []byte{}
 	}
@@ -278,38 +624,239 @@ func (e *Err) JSONOrEmpty(stackTrace ...bool) []byte {
 }
 
 // MarshalJSON implements [json.Marshaler]. It converts Value to its string
-// representation, Timestamp to a [time.Time], StackTrace to a string, and
-// drops non-serializable Details. An internal Alias type prevents infinite
-// recursion.
+// representation, Timestamp to a [time.Time], StackTrace to an array of
+// [Frame], and drops non-serializable Details. If Value is a sentinel
+// registered with [Register], its ID is emitted as "value_id", and if the
+// type of Details is registered with [RegisterDetails], its name is emitted as
+// "details_type". The symbolic name of the code is emitted as "code_name" if
+// it is declared in the catalog set with [SetDefaultCatalog]. Severity is
+// emitted as its name, e.g. "warn", RetryAfter as a duration string, e.g.
+// "1.5s", and Tags as an object keeping their order, without the values that
+// are not serializable. Msg, Details and Tags are redacted for [OutputLog] by
+// the [Redactor] set with [SetRedactor].
+//
+// The errors of the chain beyond the depth set with [SetMaxDepth] are omitted,
+// their number being emitted as "more" on the last encoded error. The Prev or
+// cause of an error leading back to one of its ancestors is omitted, and
+// "cycle" is emitted as true.
 func (e *Err) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.toJSON(OutputLog, &path{}))
+}
+
+// jsonErr is the JSON representation of an Err, built by [Err.toJSON].
+type jsonErr struct {
+	Value       string     `json:"value"`
+	ValueID     string     `json:"value_id,omitempty"`
+	CodeName    string     `json:"code_name,omitempty"`
+	Details     any        `json:"details"`
+	DetailsType string     `json:"details_type,omitempty"`
+	Tags        Fields     `json:"tags,omitempty"`
+	Timestamp   time.Time  `json:"timestamp"`
+	StackTrace  []Frame    `json:"stack_trace,omitempty"`
+	Code        int        `json:"code,omitzero"`
+	Severity    Severity   `json:"severity,omitzero"`
+	Msg         string     `json:"msg"`
+	PublicMsg   string     `json:"public_msg,omitempty"`
+	File        string     `json:"file"`
+	Line        int        `json:"line"`
+	Retryable   *bool      `json:"retryable,omitempty"`
+	Temporary   *bool      `json:"temporary,omitempty"`
+	RetryAfter  string     `json:"retry_after,omitempty"`
+	Prev        *jsonErr   `json:"prev"`
+	Causes      []*jsonErr `json:"causes,omitempty"`
+	More        int        `json:"more,omitempty"`
+	Cycle       bool       `json:"cycle,omitempty"`
+}
+
+// toJSON returns the JSON representation of the chain, building it
+// iteratively so that long chains do not grow the stack.
+func (e *Err) toJSON(out Output, p *path) *jsonErr {
+	mark := p.n
+
+	var head, last *jsonErr
+	next := &head
+	for depth, link := 0, e; link != nil; depth, link = depth+1, link.Prev {
+		if !p.push(link) {
+			last.Cycle = true
+			break
+		}
+		if truncated(depth) {
+			last.More = link.Depth()
+			break
+		}
+
+		last = link.jsonLink(out)
+		for _, cause := range link.Causes {
+			switch {
+			case cause == nil:
+			case p.contains(cause):
+				last.Cycle = true
+			default:
+				last.Causes = append(last.Causes, cause.toJSON(out, p))
+			}
+		}
+
+		*next = last
+		next = &last.Prev
+	}
+
+	p.truncate(mark)
+	return head
+}
+
+// jsonLink returns the JSON representation of e, without its Prev and Causes,
+// with its Msg, Details and Tags redacted for out.
+func (e *Err) jsonLink(out Output) *jsonErr {
+	value := ""
+	if e.Value != nil {
+		value = e.Value.Error()
+	}
+	valueID, _ := SentinelID(e.Value)
+
+	msg, details := e.redact(out)
+	if details != nil {
+		if _, err := json.Marshal(details); err != nil {
+			details = nil
+		}
+	}
+	detailsType, _ := detailsName(details)
+
+	retryAfter := ""
+	if e.RetryAfter > 0 {
+		retryAfter = e.RetryAfter.String()
+	}
+
+	return &jsonErr{
+		Value:       value,
+		ValueID:     valueID,
+		CodeName:    codeName(e.Code),
+		Details:     details,
+		DetailsType: detailsType,
+		Tags:        e.redactTags(out).encodable(),
+		Timestamp:   time.UnixMicro(e.Timestamp),
+		StackTrace:  e.StackTrace.Frames(),
+		Code:        e.Code,
+		Severity:    e.Severity,
+		Msg:         msg,
+		PublicMsg:   e.PublicMsg,
+		File:        e.File,
+		Line:        e.Line,
+		Retryable:   e.Retryable,
+		Temporary:   e.Temporary,
+		RetryAfter:  retryAfter,
+	}
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], reversing [Err.MarshalJSON].
+// The whole tree of Prev and Causes, the timestamp, the stack trace frames,
+// the code and the details are restored. If the encoded "value_id" is
+// registered with [Register], Value is restored as the registered sentinel.
+// Otherwise, it is restored as an error whose message is the encoded value;
+// two such errors with the same message are equal for [errors.Is], so
+// [Err.Eq] and [Err.ValueEq] keep working between decoded errors. An empty
+// value is restored as a nil Value.
+//
+// If the encoded "details_type" is registered with [RegisterDetails], Details
+// are decoded into the registered type. Otherwise, they are decoded with the
+// default rules of [json.Unmarshal], e.g. a JSON object becomes a
+// map[string]any.
+func (e *Err) UnmarshalJSON(data []byte) error {
 	type Alias Err // Use an alias to avoid infinite recursion
 
-	return json.Marshal(&struct {
-		Value      string    `json:"value"`
-		Details    any       `json:"details"`
-		Timestamp  time.Time `json:"timestamp"`
-		StackTrace string    `json:"stack_trace,omitempty"`
-		Alias
+	*e = Err{}
+	aux := &struct {
+		Value       string          `json:"value"`
+		ValueID     string          `json:"value_id"`
+		Details     json.RawMessage `json:"details"`
+		DetailsType string          `json:"details_type"`
+		Timestamp   time.Time       `json:"timestamp"`
+		RetryAfter  string          `json:"retry_after"`
+		*Alias
 	}{
-		Value: func() string {
-			if e.Value != nil {
-				return e.Value.Error()
-			}
-			return ""
-		}(),
-		Details: func() any {
-			if e.Details == nil {
-				return nil
-			}
-			if _, err := json.Marshal(e.Details); err != nil {
-				return nil
-			}
-			return e.Details
-		}(),
-		Timestamp:  time.UnixMicro(e.Timestamp),
-		StackTrace: string(e.StackTrace),
-		Alias:      (Alias)(*e),
+		Alias: (*Alias)(e),
+	}
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	if sentinel, ok := Lookup(aux.ValueID); ok {
+		e.Value = sentinel
+	} else if aux.Value != "" {
+		e.Value = valueError(aux.Value)
+	}
+	if !aux.Timestamp.IsZero() {
+		e.Timestamp = aux.Timestamp.UnixMicro()
+	}
+	if aux.RetryAfter != "" {
+		retryAfter, err := time.ParseDuration(aux.RetryAfter)
+		if err != nil {
+			return fmt.Errorf("invalid retry_after: %w", err)
+		}
+		e.RetryAfter = retryAfter
+	}
+	if len(aux.Details) > 0 {
+		details, err := decodeDetails(aux.Details, aux.DetailsType)
+		if err != nil {
+			return err
+		}
+		e.Details = details
+	}
+
+	return nil
+}
+
+// valueError is the Value of an Err decoded from JSON. Being a comparable
+// string type, two valueError with the same message are equal for
+// [errors.Is].
+type valueError string
+
+// Error implements the error interface, returning the decoded message.
+func (v valueError) Error() string {
+	return string(v)
+}
+
+// MaxSeverity returns the highest Severity of the errors of the tree walked by
+// [Err.Walk], so that an error wrapping or caused by a critical one is
+// critical too, e.g. the error returned by [Retry] when an attempt failed with
+// a critical error. Returns [SeverityUnspecified] if no error of the tree has
+// a severity, or if called on a nil pointer.
+func (e *Err) MaxSeverity() Severity {
+	severity := SeverityUnspecified
+	e.Walk(func(link *Err) bool {
+		severity = max(severity, link.Severity)
+		return true
 	})
+	return severity
+}
+
+// PublicMessage returns the message to show to end users: the PublicMsg of the
+// outermost error of the Prev chain that has one, or an empty string if there
+// is none. Unlike Msg, the public message is meant for client-facing
+// renderers, e.g. the detail of the problems of the httpx package, and is
+// never redacted.
+//
+// Example:
+//
+//	err := Make(ErrNotFound, WithMsg("user 42 not found in users table"), WithPublicMsg("User not found"))
+//	wrapped := Make(ErrHandler, WithMsg("cannot fetch user"), WithPrev(err))
+//	fmt.Println(wrapped.PublicMessage()) // User not found
+func (e *Err) PublicMessage() string {
+	for link := range e.All() {
+		if link.PublicMsg != "" {
+			return link.PublicMsg
+		}
+	}
+	return ""
+}
+
+// Frames returns the symbolized frames of the stack trace captured when the
+// Err was created, innermost first. Returns nil if no stack trace was captured
+// or if called on a nil pointer.
+func (e *Err) Frames() []Frame {
+	if e == nil {
+		return nil
+	}
+	return e.StackTrace.Frames()
 }
 
 // ValueEq reports whether e and other have the same Value (compared with
@@ -328,24 +875,56 @@ func (e *Err) ValueEq(other *Err) bool {
 	return errors.Is(e.Value, other.Value)
 }
 
-// Eq reports whether e and other have the same Value and an identical Prev chain
-// (each pair compared with [errors.Is]).
+// Eq reports whether e and other have the same Value and an identical tree:
+// the same Prev chain, and the same Causes for each error of the chain (each
+// pair compared with [errors.Is]). Two errors leading back to one of their
+// ancestors at the same place are considered equal.
 func (e *Err) Eq(other *Err) bool {
 	if e == nil || other == nil {
 		return e == other
 	}
-	if !errors.Is(e.Value, other.Value) {
-		return false
+	return e.eq(other, &path{}, &path{})
+}
+
+// eq implements [Err.Eq], ep and op being the paths of e and other.
+func (e *Err) eq(other *Err, ep, op *path) bool {
+	emark, omark := ep.n, op.n
+
+	el, ol := e, other
+	for el != nil && ol != nil {
+		ecycle, ocycle := !ep.push(el), !op.push(ol)
+		if ecycle || ocycle {
+			return ecycle && ocycle
+		}
+		if !errors.Is(el.Value, ol.Value) || !causesEq(el.Causes, ol.Causes, ep, op) {
+			return false
+		}
+		el, ol = el.Prev, ol.Prev
 	}
 
-	ep, op := e.Prev, other.Prev
-	for ep != nil && op != nil {
-		if !errors.Is(ep.Value, op.Value) {
+	ep.truncate(emark)
+	op.truncate(omark)
+	return el == nil && ol == nil
+}
+
+// causesEq reports whether causes and others have the same length and whether
+// each pair of causes is equal with [Err.Eq].
+func causesEq(causes, others []*Err, ep, op *path) bool {
+	if len(causes) != len(others) {
+		return false
+	}
+	for i, cause := range causes {
+		if cause == nil || others[i] == nil {
+			if cause != others[i] {
+				return false
+			}
+			continue
+		}
+		if !cause.eq(others[i], ep, op) {
 			return false
 		}
-		ep, op = ep.Prev, op.Prev
 	}
-	return ep == nil && op == nil
+	return true
 }
 
 // ToError returns the receiver as an error interface value, or nil if the Err
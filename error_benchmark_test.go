@@ -12,6 +12,44 @@ func BenchmarkErr_New(b *testing.B) {
 	}
 }
 
+func BenchmarkErr_Make(b *testing.B) {
+	for b.Loop() {
+		e := Make(errors.New("test"), WithMsg("My error message"))
+		_ = e
+	}
+}
+
+func benchmarkErr_New_StackPolicy(b *testing.B, p StackPolicy) {
+	prev := SetStackPolicy(p)
+	b.Cleanup(func() { SetStackPolicy(prev) })
+
+	root := New(errors.New("root"), "root cause", nil, 0, nil)
+	for b.Loop() {
+		e := New(errors.New("test"), "My error message", nil, 400, root)
+		_ = e
+	}
+}
+
+func BenchmarkErr_New_StackAlways(b *testing.B) {
+	benchmarkErr_New_StackPolicy(b, StackAlways)
+}
+
+func BenchmarkErr_New_StackNever(b *testing.B) {
+	benchmarkErr_New_StackPolicy(b, StackNever)
+}
+
+func BenchmarkErr_New_StackRootOnly(b *testing.B) {
+	benchmarkErr_New_StackPolicy(b, StackRootOnly)
+}
+
+func BenchmarkErr_New_StackForCodes(b *testing.B) {
+	benchmarkErr_New_StackPolicy(b, StackForCodes(500))
+}
+
+func BenchmarkErr_New_StackSampled(b *testing.B) {
+	benchmarkErr_New_StackPolicy(b, StackSampled(100))
+}
+
 func BenchmarkErr_NewSimple(b *testing.B) {
 	for b.Loop() {
 		e := NewSimple(errors.New("test"), "My error message", nil)
@@ -195,3 +233,90 @@ func BenchmarkErr_Clone_16(b *testing.B) {
 		_ = err
 	}
 }
+
+// newBenchmarkChain returns a chain of n errors with the codes n-1 to 0, the
+// outermost first.
+func newBenchmarkChain(n int) *Err {
+	var e *Err
+	for i := range n {
+		e = &Err{Value: errors.New("error"), Code: i, Prev: e}
+	}
+	return e
+}
+
+func BenchmarkErr_All_16(b *testing.B) {
+	e := newBenchmarkChain(16)
+
+	for b.Loop() {
+		n := 0
+		for range e.All() {
+			n++
+		}
+		_ = n
+	}
+}
+
+func BenchmarkErr_All_16_ManualLoop(b *testing.B) {
+	e := newBenchmarkChain(16)
+
+	for b.Loop() {
+		n := 0
+		for link := e; link != nil; link = link.Prev {
+			n++
+		}
+		_ = n
+	}
+}
+
+func BenchmarkErr_Backward_16(b *testing.B) {
+	e := newBenchmarkChain(16)
+
+	for b.Loop() {
+		for link := range e.Backward() {
+			_ = link
+		}
+	}
+}
+
+func BenchmarkErr_FindCode_16(b *testing.B) {
+	e := newBenchmarkChain(16)
+
+	for b.Loop() {
+		_ = e.FindCode(0)
+	}
+}
+
+func BenchmarkErr_FindCode_16_ManualLoop(b *testing.B) {
+	e := newBenchmarkChain(16)
+
+	for b.Loop() {
+		var found *Err
+		for link := e; link != nil; link = link.Prev {
+			if link.Code == 0 {
+				found = link
+				break
+			}
+		}
+		_ = found
+	}
+}
+
+func BenchmarkErr_Root_16(b *testing.B) {
+	e := newBenchmarkChain(16)
+
+	for b.Loop() {
+		_ = e.Root()
+	}
+}
+
+func BenchmarkErr_Root_16_ManualLoop(b *testing.B) {
+	e := newBenchmarkChain(16)
+
+	for b.Loop() {
+		root := e
+		for root.Prev != nil {
+			root = root.Prev
+		}
+		_ = root
+	}
+}
@@ -1,8 +1,14 @@
 package xerr
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"time"
 )
 
 func ExampleNew() {
@@ -17,6 +23,22 @@ func ExampleNew() {
 	// user lookup failed
 }
 
+func ExampleMake() {
+	err := Make(errors.New("not found"),
+		WithMsg("user lookup failed"),
+		WithDetails(map[string]int{"user_id": 42}),
+		WithCode(404),
+	)
+	fmt.Println(err.Value)
+	fmt.Println(err.Code)
+	fmt.Println(err.Msg)
+
+	// Output:
+	// not found
+	// 404
+	// user lookup failed
+}
+
 func ExampleNew_nil() {
 	err := New(nil, "msg", nil, 0, nil)
 	fmt.Println(err == nil)
@@ -94,10 +116,27 @@ func ExampleErr_Unwrap() {
 	inner := NewSimple(errors.New("root cause"), "inner", nil)
 	outer := inner.Wrap(errors.New("wrapper"), "outer", nil, 0)
 
-	prev := outer.Unwrap()
-	fmt.Println(prev != nil)
+	errs := outer.Unwrap()
+	fmt.Println(errs[0])
 
-	// Output: true
+	var prev *Err
+	fmt.Println(errors.As(errs[1], &prev) && prev == outer.Prev)
+
+	// Output:
+	// wrapper
+	// true
+}
+
+func ExampleErr_As() {
+	inner := NewSimple(&fs.PathError{Op: "open", Path: "/tmp/file", Err: fs.ErrNotExist}, "inner", nil)
+	outer := inner.Wrap(errors.New("wrapper"), "outer", nil, 0)
+
+	var pathErr *fs.PathError
+	if errors.As(fmt.Errorf("handler: %w", outer), &pathErr) {
+		fmt.Println(pathErr.Path)
+	}
+
+	// Output: /tmp/file
 }
 
 func ExampleErr_Clone() {
@@ -175,3 +214,237 @@ func ExampleErr_JSONOrEmpty() {
 
 	// Output: 0
 }
+
+func ExampleErr_UnmarshalJSON() {
+	err := New(errors.New("not found"), "user lookup failed", nil, 404, nil)
+	b, _ := json.Marshal(err)
+
+	var decoded Err
+	_ = json.Unmarshal(b, &decoded)
+	fmt.Println(decoded.Value)
+	fmt.Println(decoded.Code)
+	fmt.Println(decoded.Timestamp == err.Timestamp)
+
+	// Output:
+	// not found
+	// 404
+	// true
+}
+
+func ExampleRegister() {
+	errNotFound := errors.New("not found")
+	if err := Register(errNotFound, "example.not_found"); err != nil {
+		panic(err)
+	}
+
+	b, _ := New(errNotFound, "user lookup failed", nil, 404, nil).JSON()
+
+	var decoded Err
+	_ = json.Unmarshal(b, &decoded)
+	fmt.Println(decoded.Is(errNotFound))
+
+	// Output: true
+}
+
+func ExampleNewWith() {
+	type validation struct {
+		Field string
+	}
+
+	inner := NewWith(errors.New("invalid"), "invalid email", validation{Field: "email"}, 400, nil)
+	outer := inner.Wrap(errors.New("signup failed"), "cannot create user", nil, 0)
+
+	if v, ok := DetailsAs[validation](outer); ok {
+		fmt.Println(v.Field)
+	}
+	fmt.Println(len(AllDetails[validation](outer)))
+
+	// Output:
+	// email
+	// 1
+}
+
+func ExampleErr_Format() {
+	inner := NewSimple(errors.New("connection refused"), "db error", nil)
+	outer := inner.Wrap(errors.New("query failed"), "cannot fetch user", nil, 500)
+
+	fmt.Printf("%v\n", outer)
+
+	// Output: cannot fetch user: db error: connection refused
+}
+
+func ExampleAttr() {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "source" || a.Key == "timestamp" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	err := New(errors.New("not found"), "user lookup failed", nil, 404, nil)
+	logger.Error("request failed", Attr(err))
+
+	// Output: level=ERROR msg="request failed" error.value="not found" error.code=404 error.msg="user lookup failed"
+}
+
+func ExampleCatalog() {
+	const codeUserNotFound = 1001
+
+	codes := NewCatalog().MustDefine(CodeInfo{
+		Code:     codeUserNotFound,
+		Name:     "USER_NOT_FOUND",
+		Message:  "User not found",
+		Category: CategoryNotFound,
+	})
+
+	err := codes.New(codeUserNotFound, errors.New("not found"))
+	info, _ := codes.Lookup(err.Code)
+	fmt.Println(err.Msg)
+	fmt.Println(info.Name, info.Status())
+
+	// Output:
+	// User not found
+	// USER_NOT_FOUND 404
+}
+
+func ExampleJoin() {
+	errRequired := errors.New("required")
+
+	var m *Multi
+	m = m.Append(NewSimple(errRequired, "name is required", nil))
+	m = m.Append(nil)
+	m = m.Append(NewSimple(errors.New("invalid"), "age must be positive", nil))
+
+	fmt.Println(m.Len())
+	fmt.Println(errors.Is(m, errRequired))
+	for _, e := range m.Errs {
+		fmt.Println(e.Msg)
+	}
+
+	// Output:
+	// 2
+	// true
+	// name is required
+	// age must be positive
+}
+
+func ExampleErr_Walk() {
+	err := Make(errors.New("fetch failed"),
+		WithMsg("cannot fetch user"),
+		WithCauses(
+			NewSimple(errors.New("timeout"), "profile service", nil),
+			NewSimple(errors.New("connection refused"), "orders service", nil),
+		),
+	)
+
+	err.Walk(func(link *Err) bool {
+		fmt.Println(link.Msg)
+		return true
+	})
+	fmt.Printf("%v\n", err)
+
+	// Output:
+	// cannot fetch user
+	// profile service
+	// orders service
+	// cannot fetch user [profile service: timeout; orders service: connection refused]: fetch failed
+}
+
+func ExampleErr_All() {
+	root := Make(errors.New("connection refused"), WithMsg("db error"), WithCode(503))
+	err := Make(errors.New("query failed"), WithMsg("cannot fetch user"), WithCode(500), WithPrev(root))
+
+	for link := range err.All() {
+		fmt.Println(link.Msg)
+	}
+	fmt.Println(err.Depth())
+	fmt.Println(err.Root().Msg)
+	fmt.Println(err.FindCode(503).Value)
+
+	// Output:
+	// cannot fetch user
+	// db error
+	// 2
+	// db error
+	// connection refused
+}
+
+func ExampleSetMaxDepth() {
+	prev := SetMaxDepth(2)
+	defer SetMaxDepth(prev)
+
+	var err *Err
+	for i := range 5 {
+		err = Make(fmt.Errorf("attempt %d failed", 5-i), WithPrev(err))
+	}
+	fmt.Printf("%v\n", err)
+
+	// Output: attempt 1 failed: attempt 2 failed: … 3 more
+}
+
+func ExampleErr_Freeze() {
+	frozen := New(errors.New("not found"), "user lookup failed", nil, 404, nil).Freeze()
+
+	wrapped := frozen.Wrap(errors.New("handler failed"), WithMsg("cannot fetch user"), WithCode(500))
+	public := wrapped.WithMsg("user not available")
+
+	fmt.Println(wrapped.Msg())
+	fmt.Println(public.Msg())
+	fmt.Println(public.Prev().Code())
+
+	// Output:
+	// cannot fetch user
+	// user not available
+	// 404
+}
+
+func ExampleTagRedactor() {
+	type login struct {
+		User     string `json:"user"`
+		Email    string `json:"email" xerr:"redact"`
+		Password string `json:"password" xerr:"secret"`
+	}
+
+	err := Make(errors.New("login failed"), WithDetails(login{User: "bob", Email: "bob@example.com", Password: "hunter2"}))
+
+	fmt.Printf("%+v\n", err.Redacted(OutputLog).Details)
+	fmt.Printf("%+v\n", err.Redacted(OutputClient).Details)
+
+	// Output:
+	// {User:bob Email:bob@example.com Password:[REDACTED]}
+	// {User:bob Email:[REDACTED] Password:[REDACTED]}
+}
+
+func ExampleRetryable() {
+	unavailable := Make(errors.New("service unavailable"), WithTemporary(true), WithRetryAfter(2*time.Second))
+	err := Make(errors.New("cannot fetch user"), WithPrev(unavailable))
+
+	fmt.Println(Retryable(err), RetryAfter(err))
+
+	err = Make(errors.New("invalid user id"), WithRetryable(false), WithPrev(unavailable))
+	fmt.Println(Retryable(err))
+
+	// Output:
+	// true 2s
+	// false
+}
+
+func ExampleRetry() {
+	policy := RetryPolicy{MaxAttempts: 3, InitialDelay: time.Millisecond}
+	unavailable := errors.New("service unavailable")
+
+	attempt := 0
+	err := Retry(context.Background(), policy, func() *Err {
+		attempt++
+		return Make(unavailable, WithMsg(fmt.Sprintf("call %d", attempt)), WithTemporary(true))
+	})
+
+	fmt.Printf("%v\n", err)
+	fmt.Println(err.Is(unavailable))
+
+	// Output:
+	// giving up after 3 attempts [attempt 1: call 1: service unavailable; attempt 2: call 2: service unavailable; attempt 3: call 3: service unavailable]: xerr: retry failed
+	// true
+}
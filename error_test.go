@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -158,6 +160,11 @@ func TestErr_Wrap_WithSkip(t *testing.T) {
 	assert.Equal(t, err, wrappedErr.Prev)
 }
 
+func TestErr_Wrap_ReturnsNilOnNilValue(t *testing.T) {
+	err := NewSimple(errors.New("test"), "My error message", nil)
+	assert.Nil(t, err.Wrap(nil, "Wrapped message", nil, 100))
+}
+
 // ----------------------------------------------------------------------------
 //
 // Tests of Empty()
@@ -382,7 +389,19 @@ func TestUnwrap(t *testing.T) {
 		Prev:      err2,
 	}
 
-	assert.Equal(t, err.Unwrap(), err2)
+	errs := err.Unwrap()
+	assert.Len(t, errs, 2)
+	assert.Equal(t, err.Value, errs[0])
+	assert.Same(t, err2, unwrapErr(t, errs[1]))
+}
+
+// unwrapErr returns the *Err of err, an error returned by [Err.Unwrap].
+func unwrapErr(t *testing.T, err error) *Err {
+	t.Helper()
+
+	var e *Err
+	assert.True(t, errors.As(err, &e))
+	return e
 }
 
 func TestUnwrapEmpty(t *testing.T) {
@@ -398,7 +417,64 @@ func TestUnwrapEmpty(t *testing.T) {
 		Prev:      nil,
 	}
 
-	assert.Equal(t, err.Unwrap(), nil)
+	assert.Equal(t, []error{err.Value}, err.Unwrap())
+}
+
+func TestUnwrap_Nil(t *testing.T) {
+	var err *Err
+	assert.Nil(t, err.Unwrap())
+	assert.Nil(t, (&Err{}).Unwrap())
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of As()
+//
+// ----------------------------------------------------------------------------
+
+// codeError is a typed error used to test errors.As compatibility.
+type codeError struct {
+	code int
+}
+
+func (c *codeError) Error() string {
+	return fmt.Sprintf("code error %d", c.code)
+}
+
+func TestErr_As(t *testing.T) {
+	err := New(&codeError{code: 42}, "My error message", nil, 0, nil)
+
+	var target *codeError
+	assert.True(t, err.As(&target))
+	assert.Equal(t, 42, target.code)
+}
+
+func TestErr_As_NestedErrors(t *testing.T) {
+	err2 := New(&codeError{code: 2}, "My error message 2", nil, 0, nil)
+	err1 := New(errors.New("test 1"), "My error message 1", nil, 0, err2)
+	err := New(fmt.Errorf("wrapped: %w", &codeError{code: 0}), "My error message", nil, 0, err1)
+
+	var target *codeError
+	assert.True(t, err.As(&target))
+	assert.Equal(t, 0, target.code)
+
+	target = nil
+	assert.True(t, err1.As(&target))
+	assert.Equal(t, 2, target.code)
+}
+
+func TestErr_As_False(t *testing.T) {
+	err := New(errors.New("test"), "My error message", nil, 0, nil)
+
+	var target *codeError
+	assert.False(t, err.As(&target))
+	assert.Nil(t, target)
+}
+
+func TestErr_As_Nil(t *testing.T) {
+	var err *Err
+	var target *codeError
+	assert.False(t, err.As(&target))
 }
 
 // ----------------------------------------------------------------------------
@@ -531,7 +607,24 @@ func TestErr_JSON_WithStackTrace(t *testing.T) {
 	result, err := e.JSON(true)
 
 	assert.NoError(t, err)
-	assert.True(t, strings.Contains(string(result), `"stack_trace":"`))
+	assert.True(t, strings.Contains(string(result), `"stack_trace":[{"function":"`))
+}
+
+func TestErr_JSON_WithoutStackTrace_Tree(t *testing.T) {
+	e := Make(errFetchFailed,
+		WithPrev(Make(errTimeout, WithStackPolicy(StackAlways))),
+		WithCauses(Make(errDNS, WithStackPolicy(StackAlways))),
+		WithStackPolicy(StackAlways),
+	)
+
+	result, err := e.JSONFor(OutputClient)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(result), `"stack_trace"`)
+
+	result, err = e.JSON(true)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, strings.Count(string(result), `"stack_trace"`))
+	assert.NotNil(t, e.Prev.StackTrace, "the receiver must not be mutated")
 }
 
 func TestErr_JSON_WithStackTrace_Empty(t *testing.T) {
@@ -539,7 +632,7 @@ func TestErr_JSON_WithStackTrace_Empty(t *testing.T) {
 	result, err := e.JSON(true)
 
 	assert.NoError(t, err)
-	assert.False(t, strings.Contains(string(result), `"stack_trace":"`))
+	assert.False(t, strings.Contains(string(result), `"stack_trace"`))
 }
 
 func TestErr_JSON_WithNilValue(t *testing.T) {
@@ -608,6 +701,94 @@ func TestErr_MarshalJSON_NilValue(t *testing.T) {
 	assert.Contains(t, string(result), `"value":""`)
 }
 
+// ----------------------------------------------------------------------------
+//
+// Tests of UnmarshalJSON()
+//
+// ----------------------------------------------------------------------------
+
+func TestErr_UnmarshalJSON_RoundTrip(t *testing.T) {
+	details := map[string]any{"name": "John Doe", "age": float64(23)}
+	root := New(errors.New("test 2"), "My error message 2", nil, 502, nil)
+	e := New(errors.New("test"), "My error message", details, 500, root)
+
+	data, err := json.Marshal(e)
+	assert.NoError(t, err)
+
+	var decoded Err
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+
+	assert.Equal(t, "test", decoded.Value.Error())
+	assert.Equal(t, e.Code, decoded.Code)
+	assert.Equal(t, e.Msg, decoded.Msg)
+	assert.Equal(t, details, decoded.Details)
+	assert.Equal(t, e.File, decoded.File)
+	assert.Equal(t, e.Line, decoded.Line)
+	assert.Equal(t, e.Timestamp, decoded.Timestamp)
+	assert.Equal(t, e.Frames(), decoded.Frames())
+
+	assert.NotNil(t, decoded.Prev)
+	assert.Equal(t, "test 2", decoded.Prev.Value.Error())
+	assert.Equal(t, root.Code, decoded.Prev.Code)
+	assert.Equal(t, root.Msg, decoded.Prev.Msg)
+	assert.Equal(t, root.Timestamp, decoded.Prev.Timestamp)
+	assert.Equal(t, root.Frames(), decoded.Prev.Frames())
+	assert.Nil(t, decoded.Prev.Prev)
+
+	again, err := json.Marshal(&decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, data, again)
+}
+
+func TestErr_UnmarshalJSON_Comparisons(t *testing.T) {
+	root := New(errors.New("root"), "", nil, 0, nil)
+	e := New(errors.New("test"), "", nil, 0, root)
+	data, err := e.JSON()
+	assert.NoError(t, err)
+
+	var decoded1, decoded2 Err
+	assert.NoError(t, json.Unmarshal(data, &decoded1))
+	assert.NoError(t, json.Unmarshal(data, &decoded2))
+
+	assert.True(t, decoded1.Eq(&decoded2))
+	assert.True(t, decoded1.ValueEq(&decoded2))
+	assert.True(t, decoded1.Is(decoded2.Prev.Value))
+	assert.True(t, errors.Is(&decoded1, decoded2.Prev.Value))
+	assert.False(t, decoded1.Is(errors.New("root")))
+}
+
+func TestErr_UnmarshalJSON_EmptyValue(t *testing.T) {
+	var decoded Err
+	err := json.Unmarshal([]byte(`{"value":"","msg":"My error message","prev":null}`), &decoded)
+
+	assert.NoError(t, err)
+	assert.Nil(t, decoded.Value)
+	assert.Equal(t, "My error message", decoded.Msg)
+	assert.Equal(t, int64(0), decoded.Timestamp)
+	assert.Nil(t, decoded.StackTrace)
+	assert.True(t, decoded.IsEmpty())
+}
+
+func TestErr_UnmarshalJSON_ResetsReceiver(t *testing.T) {
+	decoded := New(errors.New("old"), "old message", "old details", 10, nil)
+	err := json.Unmarshal([]byte(`{"value":"new"}`), decoded)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "new", decoded.Value.Error())
+	assert.Equal(t, 0, decoded.Code)
+	assert.Equal(t, "", decoded.Msg)
+	assert.Nil(t, decoded.Details)
+	assert.Nil(t, decoded.StackTrace)
+}
+
+func TestErr_UnmarshalJSON_Invalid(t *testing.T) {
+	var decoded Err
+
+	assert.Error(t, json.Unmarshal([]byte(`{"value":1}`), &decoded))
+	assert.Error(t, json.Unmarshal([]byte(`{"timestamp":"yesterday"}`), &decoded))
+	assert.Error(t, json.Unmarshal([]byte(`{"stack_trace":"stack"}`), &decoded))
+}
+
 // ----------------------------------------------------------------------------
 //
 // Tests of JSONOrEmpty()
@@ -830,10 +1011,58 @@ func TestErr_FromError(t *testing.T) {
 	assert.Nil(t, err.Prev)
 }
 
+func TestErr_FromError_CallSite(t *testing.T) {
+	_, _, wantLine, _ := runtime.Caller(0)
+	wantLine += 2
+	err := FromError(errors.New("test"))
+
+	assert.True(t, strings.Contains(err.File, "error_test.go"))
+	assert.Equal(t, wantLine, err.Line)
+}
+
 func TestErr_FromError_Empty(t *testing.T) {
 	assert.Nil(t, FromError(nil))
 }
 
+// ----------------------------------------------------------------------------
+//
+// Tests of PublicMessage()
+//
+// ----------------------------------------------------------------------------
+
+func TestErr_PublicMessage(t *testing.T) {
+	root := Make(errDNS, WithMsg("dns error"), WithPublicMsg("Service unavailable"))
+	user := Make(errFetchFailed, WithMsg("user 42 not found"), WithPublicMsg("User not found"), WithPrev(root))
+	handler := Make(errTimeout, WithMsg("cannot fetch user"), WithPrev(user))
+
+	assert.Equal(t, "Service unavailable", root.PublicMessage())
+	assert.Equal(t, "User not found", user.PublicMessage())
+	assert.Equal(t, "User not found", handler.PublicMessage(), "outermost public message")
+}
+
+func TestErr_PublicMessage_None(t *testing.T) {
+	var e *Err
+	assert.Empty(t, e.PublicMessage())
+	assert.Empty(t, newTestTree().PublicMessage())
+}
+
+func TestErr_PublicMsg_Outputs(t *testing.T) {
+	e := Make(errFetchFailed, WithMsg("user 42 not found"), WithPublicMsg("User not found"))
+
+	assert.Contains(t, e.Error(), "msg=user 42 not found, public_msg=User not found")
+	assert.Equal(t, "user 42 not found: fetch failed", fmt.Sprintf("%v", e))
+	assert.Contains(t, fmt.Sprintf("%+v", e), "\n    msg: user 42 not found\n    public msg: User not found")
+	assert.Equal(t, "User not found", e.Clone().PublicMsg)
+
+	data, err := json.Marshal(e)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"msg":"user 42 not found","public_msg":"User not found"`)
+
+	var decoded Err
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "User not found", decoded.PublicMsg)
+}
+
 // ----------------------------------------------------------------------------
 //
 // Tests of standard error interface compatibility
@@ -850,9 +1079,64 @@ func TestErr_ErrorsIs_Compatibility(t *testing.T) {
 	sentinel := errors.New("sentinel")
 	err := New(sentinel, "wrapped", nil, 0, nil)
 
-	// Standard errors.Is works via Unwrap chain — but our Is() method handles the Prev chain.
-	// Direct value match should work via the error interface.
 	assert.True(t, err.Is(sentinel))
+	assert.True(t, errors.Is(err, sentinel))
+}
+
+func TestErr_ErrorsIs_SameAsIs(t *testing.T) {
+	sentinel1 := errors.New("sentinel 1")
+	sentinel2 := errors.New("sentinel 2")
+	sentinel3 := errors.New("sentinel 3")
+	other := errors.New("other")
+
+	err3 := New(fmt.Errorf("context: %w", sentinel3), "My error message 3", nil, 0, nil)
+	err2 := New(sentinel2, "My error message 2", nil, 0, err3)
+	err1 := New(sentinel1, "My error message 1", nil, 0, err2)
+
+	for _, target := range []error{sentinel1, sentinel2, sentinel3, other, err1, err1.Prev, err1.Prev.Prev, err3} {
+		assert.Equal(t, err1.Is(target), errors.Is(err1, target), target.Error())
+	}
+	assert.True(t, errors.Is(err1, sentinel3))
+	assert.True(t, errors.Is(err1, err1.Prev))
+	assert.False(t, errors.Is(err1, other))
+	assert.False(t, errors.Is(err1, err3)) // err3 was cloned by New
+}
+
+func TestErr_ErrorsIs_ThroughThirdPartyWrapping(t *testing.T) {
+	sentinel := errors.New("sentinel")
+	inner := New(sentinel, "inner", nil, 0, nil)
+	outer := New(errors.New("outer"), "outer", nil, 0, inner)
+	wrapped := fmt.Errorf("handler: %w", outer)
+
+	assert.True(t, errors.Is(wrapped, sentinel))
+	assert.True(t, errors.Is(fmt.Errorf("again: %w", wrapped), sentinel))
+	assert.False(t, errors.Is(wrapped, errors.New("sentinel")))
+}
+
+func TestErr_ErrorsAs_Compatibility(t *testing.T) {
+	inner := New(&codeError{code: 42}, "inner", nil, 0, nil)
+	outer := New(errors.New("outer"), "outer", nil, 0, inner)
+	wrapped := fmt.Errorf("handler: %w", outer)
+
+	var target *codeError
+	assert.True(t, errors.As(wrapped, &target))
+	assert.Equal(t, 42, target.code)
+
+	var xe *Err
+	assert.True(t, errors.As(wrapped, &xe))
+	assert.Same(t, outer, xe)
+}
+
+func TestErr_ErrorsAs_SameAsAs(t *testing.T) {
+	err2 := New(&codeError{code: 2}, "My error message 2", nil, 0, nil)
+	err1 := New(errors.New("test 1"), "My error message 1", nil, 0, err2)
+
+	var want, got *codeError
+	assert.Equal(t, err1.As(&want), errors.As(err1, &got))
+	assert.Equal(t, want, got)
+
+	var pathErr *fs.PathError
+	assert.Equal(t, err1.As(&pathErr), errors.As(err1, &pathErr))
 }
 
 func TestErr_NilIsNilError(t *testing.T) {
@@ -861,3 +1145,216 @@ func TestErr_NilIsNilError(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Nil(t, err.ToError())
 }
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Causes
+//
+// ----------------------------------------------------------------------------
+
+var (
+	errFetchFailed = errors.New("fetch failed")
+	errTimeout     = errors.New("timeout")
+	errRefused     = errors.New("connection refused")
+	errDNS         = errors.New("dns failure")
+)
+
+// newTestTree returns an error caused by two parallel failures, the second one
+// with its own chain.
+func newTestTree() *Err {
+	return Make(errFetchFailed,
+		WithMsg("cannot fetch user"),
+		WithCauses(
+			NewSimple(errTimeout, "profile service", nil),
+			NewSimple(errRefused, "orders service", NewSimple(errDNS, "", nil)),
+		),
+		WithPrev(NewSimple(errors.New("root"), "", nil)),
+	)
+}
+
+func TestErr_Walk(t *testing.T) {
+	var visited []string
+	newTestTree().Walk(func(link *Err) bool {
+		visited = append(visited, link.Value.Error())
+		return true
+	})
+
+	assert.Equal(t, []string{"fetch failed", "timeout", "connection refused", "dns failure", "root"}, visited)
+}
+
+func TestErr_Walk_Stop(t *testing.T) {
+	var visited []string
+	newTestTree().Walk(func(link *Err) bool {
+		visited = append(visited, link.Value.Error())
+		return link.Value != errRefused
+	})
+
+	assert.Equal(t, []string{"fetch failed", "timeout", "connection refused"}, visited)
+}
+
+func TestErr_Walk_Nil(t *testing.T) {
+	var e *Err
+	e.Walk(func(*Err) bool {
+		t.Fatal("fn must not be called")
+		return true
+	})
+}
+
+func TestErr_Causes_Is(t *testing.T) {
+	e := newTestTree()
+
+	assert.True(t, e.Is(errTimeout))
+	assert.True(t, e.Is(errDNS))
+	assert.True(t, e.Is(e.Causes[1].Prev))
+	assert.True(t, errors.Is(e, errDNS))
+	assert.False(t, e.Is(errors.New("timeout")))
+}
+
+func TestErr_Causes_As(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "/tmp/config", Err: fs.ErrNotExist}
+	e := Make(errFetchFailed, WithCauses(NewSimple(errTimeout, "", nil), FromError(pathErr)))
+
+	var target *fs.PathError
+	assert.True(t, e.As(&target))
+	assert.Equal(t, "/tmp/config", target.Path)
+	assert.Equal(t, e.As(&target), errors.As(e, &target))
+}
+
+func TestErr_Causes_ErrorsIs_SameAsIs(t *testing.T) {
+	e := newTestTree()
+
+	targets := []error{errFetchFailed, errTimeout, errDNS, fs.ErrNotExist, errors.New("timeout"), e, e.Prev}
+	e.Walk(func(link *Err) bool {
+		targets = append(targets, link)
+		return true
+	})
+	for _, target := range targets {
+		assert.Equal(t, e.Is(target), errors.Is(e, target), target.Error())
+	}
+
+	var want, got *fs.PathError
+	assert.Equal(t, e.As(&want), errors.As(e, &got))
+	assert.Equal(t, want, got)
+}
+
+func TestErr_Causes_Unwrap(t *testing.T) {
+	e := newTestTree()
+
+	errs := e.Unwrap()
+	assert.Len(t, errs, 4)
+	assert.Equal(t, errFetchFailed, errs[0])
+	assert.Same(t, e.Prev, unwrapErr(t, errs[1]))
+	assert.Same(t, e.Causes[0], unwrapErr(t, errs[2]))
+	assert.Same(t, e.Causes[1], unwrapErr(t, errs[3]))
+	assert.True(t, errors.Is(errs[3], e.Causes[1]))
+	assert.Equal(t, e.Causes[1].Error(), errs[3].Error())
+}
+
+func TestErr_Causes_Unwrap_Shared(t *testing.T) {
+	shared := &Err{Value: errTimeout, Msg: "shared"}
+	e := &Err{Value: errFetchFailed, Prev: shared, Causes: []*Err{shared}}
+
+	assert.Len(t, e.Unwrap(), 3)
+	assert.True(t, errors.Is(e, errTimeout))
+}
+
+func TestErr_Unwrap_Repeatable(t *testing.T) {
+	e := newTestTree()
+	errs := e.Unwrap()
+
+	for range 3 {
+		assert.True(t, errors.Is(errs[3], errDNS))
+		assert.True(t, errors.Is(e, errDNS))
+	}
+}
+
+func TestErr_Unwrap_Concurrent(t *testing.T) {
+	e := newTestTree()
+	errs := e.Unwrap()
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			for _, err := range errs {
+				_ = errors.Is(err, errDNS)
+				_ = errors.Is(err, fs.ErrNotExist)
+			}
+		})
+	}
+	wg.Wait()
+
+	assert.True(t, errors.Is(errs[3], errDNS))
+}
+
+func TestErr_Unwrap_Format(t *testing.T) {
+	e := newTestTree()
+	errs := e.Unwrap()
+
+	assert.Equal(t, fmt.Sprintf("%+v", e.Prev), fmt.Sprintf("%+v", errs[1]))
+	assert.Equal(t, fmt.Sprintf("%v", e.Causes[1]), fmt.Sprintf("%v", errs[3]))
+	assert.Equal(t, e.Causes[1].Error(), errs[3].Error())
+}
+
+func TestErr_Causes_Eq(t *testing.T) {
+	e := newTestTree()
+
+	assert.True(t, e.Eq(e.Clone()))
+	assert.False(t, e.Eq(Make(errFetchFailed, WithCauses(e.Causes[0]), WithPrev(e.Prev))))
+	assert.False(t, e.Eq(Make(errFetchFailed, WithCauses(e.Causes[1], e.Causes[0]), WithPrev(e.Prev))))
+
+	other := e.Clone()
+	other.Causes[1].Prev.Value = errors.New("dns failure")
+	assert.False(t, e.Eq(other))
+
+	deep := Make(errors.New("outer"), WithPrev(e))
+	otherDeep := Make(deep.Value, WithPrev(other))
+	assert.False(t, deep.Eq(otherDeep))
+}
+
+func TestErr_Causes_Clone(t *testing.T) {
+	e := newTestTree()
+	clone := e.Clone()
+
+	clone.Causes[1].Prev.Msg = "changed"
+	clone.Causes[0] = nil
+
+	assert.NotNil(t, e.Causes[0])
+	assert.Empty(t, e.Causes[1].Prev.Msg)
+}
+
+func TestErr_Causes_Error(t *testing.T) {
+	e := &Err{
+		Value:  errFetchFailed,
+		Causes: []*Err{{Value: errTimeout}, {Value: errRefused, Prev: &Err{Value: errDNS}}},
+	}
+
+	expected := "value=fetch failed, causes=[{value=timeout}, {value=connection refused, prev={value=dns failure}}]"
+	assert.Equal(t, expected, e.Error())
+}
+
+func TestErr_Causes_JSON(t *testing.T) {
+	e := newTestTree()
+
+	data, err := json.Marshal(e)
+	assert.NoError(t, err)
+
+	var raw map[string]any
+	assert.NoError(t, json.Unmarshal(data, &raw))
+	causes := raw["causes"].([]any)
+	assert.Len(t, causes, 2)
+	assert.Equal(t, "timeout", causes[0].(map[string]any)["value"])
+	assert.Equal(t, "dns failure", causes[1].(map[string]any)["prev"].(map[string]any)["value"])
+
+	var decoded Err
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Len(t, decoded.Causes, 2)
+	assert.Equal(t, "orders service", decoded.Causes[1].Msg)
+	assert.Equal(t, e.Causes[1].Line, decoded.Causes[1].Line)
+	assert.True(t, decoded.Causes[1].Prev.Is(valueError("dns failure")))
+}
+
+func TestErr_Causes_JSON_Omitted(t *testing.T) {
+	data, err := json.Marshal(&Err{Value: errTimeout})
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "causes")
+}
@@ -0,0 +1,187 @@
+package xerr
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Field is a key-value pair of context attached to an error, e.g. the ID of
+// the user of a request, see [Err.With].
+type Field struct {
+	Key   string
+	Value any
+}
+
+// Fields is an ordered list of key-value pairs. It is encoded in JSON as an
+// object whose members keep the order of the fields.
+type Fields []Field
+
+// Get returns the value of the field with key, and whether there is one.
+func (f Fields) Get(key string) (any, bool) {
+	for _, field := range f {
+		if field.Key == key {
+			return field.Value, true
+		}
+	}
+	return nil, false
+}
+
+// set returns f with the value of the field with key replaced by value, or
+// with a new field appended if there is none.
+func (f Fields) set(key string, value any) Fields {
+	for i, field := range f {
+		if field.Key == key {
+			f[i].Value = value
+			return f
+		}
+	}
+	return append(f, Field{Key: key, Value: value})
+}
+
+// MarshalJSON implements [json.Marshaler], encoding f as a JSON object whose
+// members are the fields, in order.
+func (f Fields) MarshalJSON() ([]byte, error) {
+	if f == nil {
+		return []byte("null"), nil
+	}
+
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, field := range f {
+		key, err := json.Marshal(field.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", field.Key, err)
+		}
+
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.Write(key)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+
+	return b.Bytes(), nil
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], reversing [Fields.MarshalJSON].
+// The values are decoded with the default rules of [json.Unmarshal], e.g. a
+// JSON number becomes a float64.
+func (f *Fields) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil {
+		return err
+	} else if tok != json.Delim('{') {
+		return fmt.Errorf("fields: expected a JSON object, got %v", tok)
+	}
+
+	fields := Fields{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		fields = append(fields, Field{Key: tok.(string), Value: value})
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	*f = fields
+	return nil
+}
+
+// writeTags writes the fields of tags to b as comma-separated key=value
+// pairs.
+func writeTags(b *strings.Builder, tags Fields) {
+	for i, field := range tags {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(b, "%s=%+v", field.Key, field.Value)
+	}
+}
+
+// encodable returns the fields of f whose value can be encoded in JSON, or
+// nil if there is none.
+func (f Fields) encodable() Fields {
+	var fields Fields
+	for _, field := range f {
+		if _, err := json.Marshal(field.Value); err == nil {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// With returns a clone of the receiver (see [Err.Clone]) with the field key
+// set to value, replacing the value of the field with the same key if there is
+// one. The receiver is not modified, so that an error shared between callers
+// can be annotated safely. Returns nil if called on a nil pointer.
+//
+// Example:
+//
+//	return err.With("user_id", userID)
+func (e *Err) With(key string, value any) *Err {
+	if e == nil {
+		return nil
+	}
+
+	clone := e.Clone()
+	clone.Tags = clone.Tags.set(key, value)
+	return clone
+}
+
+// WithFields returns a clone of the receiver (see [Err.Clone]) with the fields
+// set, sorted by key, like [Err.With] called for each of them. The receiver is
+// not modified. Returns nil if called on a nil pointer.
+//
+// Example:
+//
+//	return err.WithFields(map[string]any{"user_id": userID, "order_id": orderID})
+func (e *Err) WithFields(fields map[string]any) *Err {
+	if e == nil {
+		return nil
+	}
+
+	clone := e.Clone()
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		clone.Tags = clone.Tags.set(key, fields[key])
+	}
+	return clone
+}
+
+// Fields returns the fields of the errors of the Prev chain merged, the field
+// of an outer error overriding the one of an inner error with the same key.
+// The fields of the outermost error come first, in order, followed by the
+// fields of the inner errors not overridden. Returns nil if no error of the
+// chain has fields.
+func (e *Err) Fields() Fields {
+	var fields Fields
+	for link := range e.All() {
+		for _, field := range link.Tags {
+			if _, ok := fields.Get(field.Key); !ok {
+				fields = append(fields, field)
+			}
+		}
+	}
+	return fields
+}
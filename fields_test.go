@@ -0,0 +1,251 @@
+package xerr
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestFieldsChain returns a chain of three errors with fields.
+func newTestFieldsChain() *Err {
+	root := NewSimple(errDNS, "dns error", nil).With("host", "db.local").With("attempt", 1)
+	user := Make(errFetchFailed, WithFields(map[string]any{"user_id": 42, "attempt": 2}), WithPrev(root))
+	return NewSimple(errTimeout, "cannot fetch user", user).With("request_id", "r-1").With("user_id", 43)
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Fields
+//
+// ----------------------------------------------------------------------------
+
+func TestFields_Get(t *testing.T) {
+	fields := Fields{{Key: "user_id", Value: 42}, {Key: "order_id", Value: 7}}
+
+	value, ok := fields.Get("order_id")
+	assert.True(t, ok)
+	assert.Equal(t, 7, value)
+
+	_, ok = fields.Get("missing")
+	assert.False(t, ok)
+}
+
+func TestFields_MarshalJSON(t *testing.T) {
+	fields := Fields{{Key: "z", Value: 1}, {Key: "a", Value: "x"}, {Key: "m", Value: nil}}
+
+	data, err := json.Marshal(fields)
+
+	require.NoError(t, err)
+	assert.Equal(t, `{"z":1,"a":"x","m":null}`, string(data))
+}
+
+func TestFields_MarshalJSON_Nil(t *testing.T) {
+	data, err := json.Marshal(Fields(nil))
+
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(data))
+}
+
+func TestFields_MarshalJSON_Error(t *testing.T) {
+	_, err := json.Marshal(Fields{{Key: "fn", Value: func() {}}})
+	assert.Error(t, err)
+}
+
+func TestFields_UnmarshalJSON(t *testing.T) {
+	var fields Fields
+
+	require.NoError(t, json.Unmarshal([]byte(`{"z":1,"a":{"b":true},"m":null}`), &fields))
+	assert.Equal(t, Fields{
+		{Key: "z", Value: float64(1)},
+		{Key: "a", Value: map[string]any{"b": true}},
+		{Key: "m", Value: nil},
+	}, fields)
+}
+
+func TestFields_UnmarshalJSON_Invalid(t *testing.T) {
+	var fields Fields
+
+	assert.Error(t, json.Unmarshal([]byte(`[1, 2]`), &fields))
+	assert.Error(t, fields.UnmarshalJSON([]byte(`{"a":`)))
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of With() and WithFields()
+//
+// ----------------------------------------------------------------------------
+
+func TestErr_With(t *testing.T) {
+	e := NewSimple(errFetchFailed, "", nil)
+
+	got := e.With("user_id", 42).With("order_id", 7).With("user_id", 43)
+
+	assert.NotSame(t, e, got)
+	assert.Nil(t, e.Tags)
+	assert.Equal(t, Fields{{Key: "user_id", Value: 43}, {Key: "order_id", Value: 7}}, got.Tags)
+	assert.True(t, got.ValueEq(e))
+}
+
+func TestErr_With_DoesNotModifyReceiver(t *testing.T) {
+	e := newTestFieldsChain()
+
+	got := e.With("user_id", 44)
+	got.Prev.Tags[0].Value = "changed"
+
+	assert.Equal(t, newTestFieldsChain().Fields(), e.Fields())
+	assert.Equal(t, Fields{{Key: "request_id", Value: "r-1"}, {Key: "user_id", Value: 44}}, got.Tags)
+	assert.Equal(t, e.Line, got.Line)
+}
+
+func TestErr_With_Nil(t *testing.T) {
+	var e *Err
+	assert.Nil(t, e.With("user_id", 42))
+	assert.Nil(t, e.WithFields(map[string]any{"user_id": 42}))
+}
+
+func TestErr_WithFields(t *testing.T) {
+	e := NewSimple(errFetchFailed, "", nil).With("user_id", 42)
+
+	got := e.WithFields(map[string]any{"user_id": 43, "order_id": 7, "cart_id": 1})
+
+	assert.Equal(t, Fields{{Key: "user_id", Value: 42}}, e.Tags)
+	assert.Equal(t, Fields{
+		{Key: "user_id", Value: 43},
+		{Key: "cart_id", Value: 1},
+		{Key: "order_id", Value: 7},
+	}, got.Tags)
+	assert.Equal(t, got.Tags, e.WithFields(nil).With("user_id", 43).WithFields(map[string]any{"order_id": 7, "cart_id": 1}).Tags)
+}
+
+func TestErr_Make_WithFields(t *testing.T) {
+	e := Make(errFetchFailed,
+		WithFields(map[string]any{"user_id": 42, "order_id": 7}),
+		WithFields(map[string]any{"user_id": 43, "cart_id": 1}),
+	)
+
+	assert.Equal(t, Fields{
+		{Key: "order_id", Value: 7},
+		{Key: "user_id", Value: 43},
+		{Key: "cart_id", Value: 1},
+	}, e.Tags)
+}
+
+func TestErr_Make_WithFields_NotShared(t *testing.T) {
+	opt := WithFields(map[string]any{"user_id": 42})
+	e1 := Make(errFetchFailed, opt)
+	e2 := Make(errFetchFailed, opt)
+
+	e1.Tags[0].Value = 43
+
+	assert.Equal(t, Fields{{Key: "user_id", Value: 42}}, e2.Tags)
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Fields()
+//
+// ----------------------------------------------------------------------------
+
+func TestErr_Fields(t *testing.T) {
+	assert.Equal(t, Fields{
+		{Key: "request_id", Value: "r-1"},
+		{Key: "user_id", Value: 43},
+		{Key: "attempt", Value: 2},
+		{Key: "host", Value: "db.local"},
+	}, newTestFieldsChain().Fields())
+}
+
+func TestErr_Fields_None(t *testing.T) {
+	var e *Err
+
+	assert.Nil(t, e.Fields())
+	assert.Nil(t, newTestTree().Fields())
+}
+
+func TestErr_Fields_Clone(t *testing.T) {
+	e := newTestFieldsChain()
+	clone := e.Clone()
+
+	e.Tags[1].Value = 0
+	e.Prev.Tags[0].Value = 0
+
+	assert.Equal(t, newTestFieldsChain().Fields(), clone.Fields())
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of the fields of the outputs
+//
+// ----------------------------------------------------------------------------
+
+func TestErr_Fields_Outputs(t *testing.T) {
+	e := NewSimple(errFetchFailed, "cannot fetch user", nil).With("user_id", 42).With("order_id", 7)
+
+	assert.Contains(t, e.Error(), "msg=cannot fetch user, tags=[user_id=42, order_id=7], source=")
+	assert.Contains(t, fmt.Sprintf("%+v", e), "\n    tags: user_id=42, order_id=7\n")
+	assert.Contains(t, fmt.Sprintf("%#v", e), `Tags:xerr.Fields{xerr.Field{Key:"user_id", Value:42}, xerr.Field{Key:"order_id", Value:7}}`)
+
+	record := logJSON(t, "error", e)["error"].(map[string]any)
+	assert.Equal(t, map[string]any{"user_id": float64(42), "order_id": float64(7)}, record["tags"])
+}
+
+func TestErr_Fields_JSON(t *testing.T) {
+	e := newTestFieldsChain()
+
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"tags":{"request_id":"r-1","user_id":43}`)
+
+	var decoded Err
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, Fields{
+		{Key: "request_id", Value: "r-1"},
+		{Key: "user_id", Value: float64(43)},
+		{Key: "attempt", Value: float64(2)},
+		{Key: "host", Value: "db.local"},
+	}, decoded.Fields())
+}
+
+func TestErr_Fields_JSON_NotSerializable(t *testing.T) {
+	e := NewSimple(errFetchFailed, "", nil).With("fn", func() {}).With("user_id", 42)
+
+	data, err := json.Marshal(e)
+
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"tags":{"user_id":42}`)
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of the fields of Frozen
+//
+// ----------------------------------------------------------------------------
+
+func TestFrozen_With_Fields(t *testing.T) {
+	f := newTestFieldsChain().Freeze()
+	g := f.With("user_id", 44).With("session", "s-1")
+
+	assert.Equal(t, Fields{{Key: "request_id", Value: "r-1"}, {Key: "user_id", Value: 43}}, f.Tags())
+	assert.Equal(t, Fields{
+		{Key: "request_id", Value: "r-1"},
+		{Key: "user_id", Value: 44},
+		{Key: "session", Value: "s-1"},
+	}, g.Tags())
+	host, _ := g.Fields().Get("host")
+	assert.Equal(t, "db.local", host)
+
+	tags := f.Tags()
+	tags[0].Value = "changed"
+	assert.Equal(t, "r-1", f.Tags()[0].Value)
+}
+
+func TestFrozen_Fields_Nil(t *testing.T) {
+	var f *Frozen
+
+	assert.Nil(t, f.Tags())
+	assert.Nil(t, f.Fields())
+	assert.Nil(t, f.With("user_id", 42))
+}
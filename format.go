@@ -0,0 +1,244 @@
+package xerr
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// Format implements [fmt.Formatter]. It is nil-safe and supports the
+// following verbs:
+//
+//	%s, %v  the short message chain, e.g. "cannot fetch user: db error: connection refused"
+//	%q      the short message chain, double-quoted
+//	%+v     a multi-line report with each link of the tree, its source and its stack frames
+//	%#v     a Go-syntax representation of the Err
+//
+// Msg, Details and Tags are redacted for [OutputLog] by the [Redactor] set
+// with [SetRedactor]. Use [Err.Error] to get every field formatted as
+// key=value pairs.
+func (e *Err) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		switch {
+		case s.Flag('+'):
+			_, _ = io.WriteString(s, e.report())
+		case s.Flag('#'):
+			_, _ = io.WriteString(s, e.goString())
+		default:
+			_, _ = io.WriteString(s, e.message())
+		}
+	case 's':
+		_, _ = io.WriteString(s, e.message())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", e.message())
+	default:
+		_, _ = fmt.Fprintf(s, "%%!%c(*xerr.Err=%s)", verb, e.message())
+	}
+}
+
+// message returns the short message chain: the Msg of each link of the chain,
+// or its Value if Msg is empty, followed by the Value of the root error,
+// separated by ": ". The short message chains of the Causes of a link follow
+// it in brackets, separated by "; ".
+func (e *Err) message() string {
+	if e == nil {
+		return "<nil>"
+	}
+	return e.shortMessage(&path{})
+}
+
+// shortMessage implements [Err.message]. The links beyond the depth set with
+// [SetMaxDepth] are replaced by "… N more", and a link leading back to one of
+// its ancestors by "… cycle".
+func (e *Err) shortMessage(p *path) string {
+	mark := p.n
+
+	var parts []string
+	var root *Err
+	for depth, link := 0, e; link != nil; depth, link = depth+1, link.Prev {
+		root = nil
+		if !p.push(link) {
+			parts = append(parts, "… cycle")
+			break
+		}
+		if truncated(depth) {
+			parts = append(parts, fmt.Sprintf("… %d more", link.Depth()))
+			break
+		}
+		root = link
+
+		msg, _ := link.redact(OutputLog)
+
+		var part string
+		switch {
+		case msg != "":
+			part = msg
+		case link.Value != nil:
+			part = fmt.Sprint(link.Value)
+		}
+		if len(link.Causes) > 0 {
+			causes := make([]string, 0, len(link.Causes))
+			for _, cause := range link.Causes {
+				if p.contains(cause) {
+					causes = append(causes, "… cycle")
+				} else {
+					causes = append(causes, cause.shortMessage(p))
+				}
+			}
+			part = strings.TrimSpace(part + " [" + strings.Join(causes, "; ") + "]")
+		}
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if root != nil && root.Value != nil {
+		if msg, _ := root.redact(OutputLog); msg != "" {
+			parts = append(parts, fmt.Sprint(root.Value))
+		}
+	}
+
+	p.truncate(mark)
+	return strings.Join(parts, ": ")
+}
+
+// report returns the multi-line report of the tree: the short message chain
+// followed by the fields, source and stack frames of each link. The Causes of
+// a link are reported below it, indented.
+func (e *Err) report() string {
+	if e == nil {
+		return "<nil>"
+	}
+
+	var b strings.Builder
+	b.WriteString(e.message())
+	e.writeReport(&b, "", &path{})
+
+	return b.String()
+}
+
+// writeReport writes the fields, source and stack frames of each link of the
+// chain to b, each line prefixed by indent, followed by its Causes. The links
+// beyond the depth set with [SetMaxDepth] are replaced by "… N more", and a
+// link leading back to one of its ancestors by "… cycle".
+func (e *Err) writeReport(b *strings.Builder, indent string, p *path) {
+	mark := p.n
+
+	for i, link := 0, e; link != nil; i, link = i+1, link.Prev {
+		if !p.push(link) {
+			fmt.Fprintf(b, "\n%s[%d] … cycle", indent, i)
+			break
+		}
+		if truncated(i) {
+			fmt.Fprintf(b, "\n%s[%d] … %d more", indent, i, link.Depth())
+			break
+		}
+
+		fmt.Fprintf(b, "\n%s[%d] %v", indent, i, link.Value)
+		msg, details := link.redact(OutputLog)
+		if msg != "" {
+			fmt.Fprintf(b, "\n%s    msg: %s", indent, msg)
+		}
+		if link.PublicMsg != "" {
+			fmt.Fprintf(b, "\n%s    public msg: %s", indent, link.PublicMsg)
+		}
+		if name := codeName(link.Code); name != "" {
+			fmt.Fprintf(b, "\n%s    code: %d (%s)", indent, link.Code, name)
+		} else if link.Code != 0 {
+			fmt.Fprintf(b, "\n%s    code: %d", indent, link.Code)
+		}
+		if link.Severity != SeverityUnspecified {
+			fmt.Fprintf(b, "\n%s    severity: %s", indent, link.Severity)
+		}
+		if details != nil {
+			fmt.Fprintf(b, "\n%s    details: %+v", indent, details)
+		}
+		if tags := link.redactTags(OutputLog); len(tags) > 0 {
+			fmt.Fprintf(b, "\n%s    tags: ", indent)
+			writeTags(b, tags)
+		}
+		if link.File != "" {
+			fmt.Fprintf(b, "\n%s    source: %s:%d", indent, link.File, link.Line)
+		}
+		if link.Timestamp != 0 {
+			fmt.Fprintf(b, "\n%s    timestamp: %s", indent, time.UnixMicro(link.Timestamp).Format(time.RFC3339Nano))
+		}
+		if link.Retryable != nil {
+			fmt.Fprintf(b, "\n%s    retryable: %t", indent, *link.Retryable)
+		}
+		if link.Temporary != nil {
+			fmt.Fprintf(b, "\n%s    temporary: %t", indent, *link.Temporary)
+		}
+		if link.RetryAfter > 0 {
+			fmt.Fprintf(b, "\n%s    retry after: %s", indent, link.RetryAfter)
+		}
+		if frames := link.Frames(); len(frames) > 0 {
+			fmt.Fprintf(b, "\n%s    stack:", indent)
+			for _, frame := range frames {
+				fmt.Fprintf(b, "\n%s        %s\n%s            %s:%d", indent, frame.Function, indent, frame.File, frame.Line)
+			}
+		}
+		if len(link.Causes) > 0 {
+			fmt.Fprintf(b, "\n%s    causes:", indent)
+			for _, cause := range link.Causes {
+				cause.writeReport(b, indent+"        ", p)
+			}
+		}
+	}
+
+	p.truncate(mark)
+}
+
+// goString returns the Go-syntax representation of the Err.
+func (e *Err) goString() string {
+	return e.goStringAt(0, &path{})
+}
+
+// goStringAt implements [Err.goString] for the link at index depth of a chain.
+// The links beyond the depth set with [SetMaxDepth] are replaced by
+// "(*xerr.Err)(… N more)", and a link leading back to one of its ancestors by
+// "(*xerr.Err)(… cycle)".
+func (e *Err) goStringAt(depth int, p *path) string {
+	if e == nil {
+		return "(*xerr.Err)(nil)"
+	}
+	if !p.push(e) {
+		return "(*xerr.Err)(… cycle)"
+	}
+	defer p.truncate(p.n - 1)
+	if truncated(depth) {
+		return fmt.Sprintf("(*xerr.Err)(… %d more)", e.Depth())
+	}
+
+	msg, details := e.redact(OutputLog)
+	tags := e.redactTags(OutputLog)
+
+	stack := "nil"
+	if e.StackTrace != nil {
+		stack = fmt.Sprintf("%p", e.StackTrace)
+	}
+
+	causes := "[]*xerr.Err(nil)"
+	if e.Causes != nil {
+		parts := make([]string, 0, len(e.Causes))
+		for _, cause := range e.Causes {
+			parts = append(parts, cause.goStringAt(0, p))
+		}
+		causes = "[]*xerr.Err{" + strings.Join(parts, ", ") + "}"
+	}
+
+	return fmt.Sprintf(
+		"&xerr.Err{Value:%#v, Code:%d, Severity:%d, Msg:%q, PublicMsg:%q, Details:%#v, Tags:%#v, File:%q, Line:%d, Timestamp:%d, Prev:%s, StackTrace:(*xerr.Stack)(%s), Causes:%s, Retryable:%s, Temporary:%s, RetryAfter:%d}",
+		e.Value, e.Code, e.Severity, msg, e.PublicMsg, details, tags, e.File, e.Line, e.Timestamp, e.Prev.goStringAt(depth+1, p), stack, causes,
+		goFlag(e.Retryable), goFlag(e.Temporary), e.RetryAfter,
+	)
+}
+
+// goFlag returns the Go-syntax representation of the optional flag b.
+func goFlag(b *bool) string {
+	if b == nil {
+		return "(*bool)(nil)"
+	}
+	return fmt.Sprintf("new(%t)", *b)
+}
@@ -0,0 +1,155 @@
+package xerr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Format()
+//
+// ----------------------------------------------------------------------------
+
+func TestErr_Format_Short(t *testing.T) {
+	inner := NewSimple(errors.New("connection refused"), "db error", nil)
+	outer := inner.Wrap(errors.New("query failed"), "cannot fetch user", nil, 500)
+
+	expected := "cannot fetch user: db error: connection refused"
+	assert.Equal(t, expected, fmt.Sprintf("%v", outer))
+	assert.Equal(t, expected, fmt.Sprintf("%s", outer))
+	assert.Equal(t, `"`+expected+`"`, fmt.Sprintf("%q", outer))
+	assert.Equal(t, "%!d(*xerr.Err="+expected+")", fmt.Sprintf("%d", outer))
+}
+
+func TestErr_Format_Short_WithoutMsg(t *testing.T) {
+	root := FromError(errors.New("connection refused"))
+	middle := NewSimple(errors.New("query failed"), "", root)
+	outer := NewSimple(errors.New("handler failed"), "cannot fetch user", middle)
+
+	assert.Equal(t, "connection refused", fmt.Sprintf("%v", root))
+	assert.Equal(t, "cannot fetch user: query failed: connection refused", fmt.Sprintf("%v", outer))
+}
+
+func TestErr_Format_Short_ErrValue(t *testing.T) {
+	inner := NewSimple(errors.New("connection refused"), "db error", nil)
+	outer := NewSimple(inner, "", nil)
+
+	assert.Equal(t, "db error: connection refused", fmt.Sprintf("%v", outer))
+}
+
+func TestErr_Format_Report(t *testing.T) {
+	now := time.Now().UnixMicro()
+	e := &Err{
+		Value:     errors.New("query failed"),
+		Code:      500,
+		Msg:       "cannot fetch user",
+		Details:   map[string]int{"id": 42},
+		File:      "error_test.go",
+		Line:      26,
+		Timestamp: now,
+		Prev: &Err{
+			Value: errors.New("connection refused"),
+			Msg:   "db error",
+		},
+	}
+
+	expected := "cannot fetch user: db error: connection refused\n" +
+		"[0] query failed\n" +
+		"    msg: cannot fetch user\n" +
+		"    code: 500\n" +
+		"    details: map[id:42]\n" +
+		"    source: error_test.go:26\n" +
+		"    timestamp: " + time.UnixMicro(now).Format(time.RFC3339Nano) + "\n" +
+		"[1] connection refused\n" +
+		"    msg: db error"
+
+	assert.Equal(t, expected, fmt.Sprintf("%+v", e))
+}
+
+func TestErr_Format_Report_StackFrames(t *testing.T) {
+	e := New(errors.New("test"), "My error message", nil, 0, nil)
+	report := fmt.Sprintf("%+v", e)
+
+	assert.Contains(t, report, "\n    stack:\n        ")
+	assert.Contains(t, report, "TestErr_Format_Report_StackFrames\n            ")
+	assert.Contains(t, report, "format_test.go:")
+}
+
+func TestErr_Format_Short_Causes(t *testing.T) {
+	e := newTestTree()
+
+	assert.Equal(t, "cannot fetch user [profile service: timeout; orders service: dns failure]: root", fmt.Sprintf("%v", e))
+	assert.Equal(t, "[timeout]", fmt.Sprintf("%v", &Err{Causes: []*Err{{Value: errTimeout}}}))
+}
+
+func TestErr_Format_Report_Causes(t *testing.T) {
+	e := &Err{
+		Value: errFetchFailed,
+		Msg:   "cannot fetch user",
+		Causes: []*Err{
+			{Value: errTimeout, Code: 504},
+			{Value: errRefused, Prev: &Err{Value: errDNS, Causes: []*Err{{Value: errors.New("no route")}}}},
+		},
+		Prev: &Err{Value: errors.New("root")},
+	}
+
+	expected := "cannot fetch user [timeout; connection refused: dns failure [no route]]: root\n" +
+		"[0] fetch failed\n" +
+		"    msg: cannot fetch user\n" +
+		"    causes:\n" +
+		"        [0] timeout\n" +
+		"            code: 504\n" +
+		"        [0] connection refused\n" +
+		"        [1] dns failure\n" +
+		"            causes:\n" +
+		"                [0] no route\n" +
+		"[1] root"
+
+	assert.Equal(t, expected, fmt.Sprintf("%+v", e))
+}
+
+func TestErr_Format_GoSyntax(t *testing.T) {
+	e := &Err{
+		Value:     errors.New("test"),
+		Code:      10,
+		Msg:       "My error message",
+		File:      "error_test.go",
+		Line:      26,
+		Timestamp: 1,
+	}
+
+	expected := `&xerr.Err{Value:&errors.errorString{s:"test"}, Code:10, Severity:0, Msg:"My error message", PublicMsg:"", ` +
+		`Details:<nil>, Tags:xerr.Fields(nil), File:"error_test.go", Line:26, Timestamp:1, Prev:(*xerr.Err)(nil), StackTrace:(*xerr.Stack)(nil), Causes:[]*xerr.Err(nil), ` +
+		`Retryable:(*bool)(nil), Temporary:(*bool)(nil), RetryAfter:0}`
+	assert.Equal(t, expected, fmt.Sprintf("%#v", e))
+}
+
+func TestErr_Format_GoSyntax_Nested(t *testing.T) {
+	e := New(errors.New("test"), "", nil, 0, NewSimple(errors.New("root"), "", nil))
+	result := fmt.Sprintf("%#v", e)
+
+	assert.Equal(t, 2, strings.Count(result, "&xerr.Err{"))
+	assert.Contains(t, result, fmt.Sprintf("StackTrace:(*xerr.Stack)(%p)", e.StackTrace))
+}
+
+func TestErr_Format_Nil(t *testing.T) {
+	var e *Err
+
+	assert.Equal(t, "<nil>", fmt.Sprintf("%v", e))
+	assert.Equal(t, "<nil>", fmt.Sprintf("%s", e))
+	assert.Equal(t, "<nil>", fmt.Sprintf("%+v", e))
+	assert.Equal(t, "(*xerr.Err)(nil)", fmt.Sprintf("%#v", e))
+}
+
+func TestErr_Format_Empty(t *testing.T) {
+	e := &Err{Msg: "My error message"}
+
+	assert.Equal(t, "My error message", fmt.Sprintf("%v", e))
+	assert.Equal(t, "", fmt.Sprintf("%v", &Err{}))
+}
@@ -0,0 +1,447 @@
+package xerr
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// Frozen is an immutable *Err: its fields are only readable through
+// accessors, and its With* methods return a modified copy, so a *Frozen can
+// be shared between goroutines, e.g. logged from one while another wraps it,
+// without synchronization.
+//
+// A *Frozen is created with [Err.Freeze], and [Frozen.Thaw] returns a mutable
+// copy. Its JSON encoding is the one of [Err.MarshalJSON].
+//
+// Since the errors of a *Frozen are never mutated, the copies share their
+// Prev and Causes instead of cloning them. The Details and Value are shared
+// too: they must not be mutated once frozen.
+type Frozen struct {
+	e *Err
+}
+
+// Freeze returns an immutable copy of the receiver. Returns nil if called on a
+// nil pointer.
+//
+// Example:
+//
+//	frozen := New(ErrNotFound, "user lookup failed", nil, 404, nil).Freeze()
+//	go logger.Error("request failed", "error", frozen)
+//	wrapped := frozen.Wrap(ErrHandler, WithMsg("cannot fetch user"))
+func (e *Err) Freeze() *Frozen {
+	if e == nil {
+		return nil
+	}
+	return &Frozen{e: e.Clone()}
+}
+
+// frozen returns a *Frozen sharing e, which must never be mutated. Returns nil
+// if e is nil.
+func frozen(e *Err) *Frozen {
+	if e == nil {
+		return nil
+	}
+	return &Frozen{e: e}
+}
+
+// Thaw returns a mutable deep copy of the receiver. Returns nil if called on a
+// nil pointer.
+func (f *Frozen) Thaw() *Err {
+	if f == nil {
+		return nil
+	}
+	return f.e.Clone()
+}
+
+// Value returns the error value. Returns nil if called on a nil pointer.
+func (f *Frozen) Value() error {
+	if f == nil {
+		return nil
+	}
+	return f.e.Value
+}
+
+// Code returns the code of the error.
+func (f *Frozen) Code() int {
+	if f == nil {
+		return 0
+	}
+	return f.e.Code
+}
+
+// Severity returns the severity level of the error.
+func (f *Frozen) Severity() Severity {
+	if f == nil {
+		return SeverityUnspecified
+	}
+	return f.e.Severity
+}
+
+// MaxSeverity returns the highest severity level of the tree, see
+// [Err.MaxSeverity].
+func (f *Frozen) MaxSeverity() Severity {
+	return f.err().MaxSeverity()
+}
+
+// Msg returns the human-readable message of the error.
+func (f *Frozen) Msg() string {
+	if f == nil {
+		return ""
+	}
+	return f.e.Msg
+}
+
+// PublicMsg returns the message of the error shown to end users.
+func (f *Frozen) PublicMsg() string {
+	if f == nil {
+		return ""
+	}
+	return f.e.PublicMsg
+}
+
+// PublicMessage returns the public message of the outermost error of the
+// chain that has one, see [Err.PublicMessage].
+func (f *Frozen) PublicMessage() string {
+	return f.err().PublicMessage()
+}
+
+// Details returns the arbitrary details attached to the error.
+func (f *Frozen) Details() any {
+	if f == nil {
+		return nil
+	}
+	return f.e.Details
+}
+
+// Tags returns a copy of the fields attached to the error.
+func (f *Frozen) Tags() Fields {
+	if f == nil {
+		return nil
+	}
+	return slices.Clone(f.e.Tags)
+}
+
+// Fields returns the fields of the errors of the chain merged, see
+// [Err.Fields].
+func (f *Frozen) Fields() Fields {
+	return f.err().Fields()
+}
+
+// File returns the file of the call site of the error.
+func (f *Frozen) File() string {
+	if f == nil {
+		return ""
+	}
+	return f.e.File
+}
+
+// Line returns the line of the call site of the error.
+func (f *Frozen) Line() int {
+	if f == nil {
+		return 0
+	}
+	return f.e.Line
+}
+
+// Timestamp returns the time of creation of the error. Returns the zero time
+// if called on a nil pointer.
+func (f *Frozen) Timestamp() time.Time {
+	if f == nil {
+		return time.Time{}
+	}
+	return time.UnixMicro(f.e.Timestamp)
+}
+
+// Retryable reports whether the failed operation is worth retrying, according
+// to the classification of the chain, see [Retryable].
+func (f *Frozen) Retryable() bool {
+	return f.err().retryable()
+}
+
+// Temporary reports whether the error is caused by a transient condition,
+// according to the classification of the chain, see [Temporary].
+func (f *Frozen) Temporary() bool {
+	return f.err().temporary()
+}
+
+// RetryAfter returns the minimum delay before retrying the failed operation,
+// see [RetryAfter].
+func (f *Frozen) RetryAfter() time.Duration {
+	return f.err().retryAfter()
+}
+
+// StackTrace returns a copy of the stack trace captured when the error was
+// created, so that its frames cannot be replaced, or nil if there is none.
+func (f *Frozen) StackTrace() *Stack {
+	if f == nil {
+		return nil
+	}
+	return f.e.StackTrace.clone()
+}
+
+// Frames returns the symbolized frames of the stack trace, innermost first.
+func (f *Frozen) Frames() []Frame {
+	if f == nil {
+		return nil
+	}
+	return f.e.Frames()
+}
+
+// Prev returns the previous error of the chain, or nil if there is none.
+func (f *Frozen) Prev() *Frozen {
+	if f == nil {
+		return nil
+	}
+	return frozen(f.e.Prev)
+}
+
+// Causes returns the causes of the error, or nil if there is none.
+func (f *Frozen) Causes() []*Frozen {
+	if f == nil || len(f.e.Causes) == 0 {
+		return nil
+	}
+
+	causes := make([]*Frozen, 0, len(f.e.Causes))
+	for _, cause := range f.e.Causes {
+		if cause != nil {
+			causes = append(causes, frozen(cause))
+		}
+	}
+	return causes
+}
+
+// with returns a copy of the receiver modified by fn. The copy shares the
+// Prev and Causes of the receiver. Returns nil if called on a nil pointer.
+func (f *Frozen) with(fn func(e *Err)) *Frozen {
+	if f == nil {
+		return nil
+	}
+
+	e := *f.e
+	fn(&e)
+	return &Frozen{e: &e}
+}
+
+// WithValue returns a copy of the receiver with the error value set to value.
+func (f *Frozen) WithValue(value error) *Frozen {
+	return f.with(func(e *Err) { e.Value = value })
+}
+
+// WithMsg returns a copy of the receiver with the message set to msg.
+func (f *Frozen) WithMsg(msg string) *Frozen {
+	return f.with(func(e *Err) { e.Msg = msg })
+}
+
+// WithPublicMsg returns a copy of the receiver with the public message set to
+// msg.
+func (f *Frozen) WithPublicMsg(msg string) *Frozen {
+	return f.with(func(e *Err) { e.PublicMsg = msg })
+}
+
+// WithCode returns a copy of the receiver with the code set to code.
+func (f *Frozen) WithCode(code int) *Frozen {
+	return f.with(func(e *Err) { e.Code = code })
+}
+
+// WithSeverity returns a copy of the receiver with the severity level set to
+// s.
+func (f *Frozen) WithSeverity(s Severity) *Frozen {
+	return f.with(func(e *Err) { e.Severity = s })
+}
+
+// WithDetails returns a copy of the receiver with the details set to details.
+func (f *Frozen) WithDetails(details any) *Frozen {
+	return f.with(func(e *Err) { e.Details = details })
+}
+
+// WithRetryable returns a copy of the receiver classified as retryable or not,
+// see [WithRetryable].
+func (f *Frozen) WithRetryable(retryable bool) *Frozen {
+	return f.with(func(e *Err) { e.Retryable = new(retryable) })
+}
+
+// WithTemporary returns a copy of the receiver classified as temporary or not,
+// see [WithTemporary].
+func (f *Frozen) WithTemporary(temporary bool) *Frozen {
+	return f.with(func(e *Err) { e.Temporary = new(temporary) })
+}
+
+// WithRetryAfter returns a copy of the receiver with the retry delay set to d.
+func (f *Frozen) WithRetryAfter(d time.Duration) *Frozen {
+	return f.with(func(e *Err) { e.RetryAfter = d })
+}
+
+// With returns a copy of the receiver with the field key set to value, see
+// [Err.With].
+func (f *Frozen) With(key string, value any) *Frozen {
+	return f.with(func(e *Err) { e.Tags = slices.Clone(e.Tags).set(key, value) })
+}
+
+// WithPrev returns a copy of the receiver with the previous error set to prev.
+func (f *Frozen) WithPrev(prev *Frozen) *Frozen {
+	return f.with(func(e *Err) { e.Prev = prev.err() })
+}
+
+// WithCauses returns a copy of the receiver with the causes set to causes.
+// Nil causes are skipped.
+func (f *Frozen) WithCauses(causes ...*Frozen) *Frozen {
+	return f.with(func(e *Err) {
+		e.Causes = nil
+		for _, cause := range causes {
+			if cause != nil {
+				e.Causes = append(e.Causes, cause.e)
+			}
+		}
+	})
+}
+
+// Wrap creates a new *Frozen with value, configured by the given options,
+// chaining the receiver as Prev. Unlike [Err.Wrap], the receiver is shared
+// rather than cloned. Returns nil if value is nil.
+//
+// The call site (File, Line) is the caller of Wrap unless overridden with
+// [WithSkip]. The [WithPrev] option is ignored.
+func (f *Frozen) Wrap(value error, opts ...Option) *Frozen {
+	o := newOptions(opts)
+	o.prev, o.sharePrev = f.err(), true
+
+	return frozen(build(value, o))
+}
+
+// err returns the *Err of the receiver, which must never be mutated, or nil
+// if called on a nil pointer.
+func (f *Frozen) err() *Err {
+	if f == nil {
+		return nil
+	}
+	return f.e
+}
+
+// Error implements the error interface, see [Err.Error].
+func (f *Frozen) Error() string {
+	return f.err().Error()
+}
+
+// Format implements [fmt.Formatter], see [Err.Format].
+func (f *Frozen) Format(s fmt.State, verb rune) {
+	f.err().Format(s, verb)
+}
+
+// LogValue implements [slog.LogValuer], see [Err.LogValue].
+func (f *Frozen) LogValue() slog.Value {
+	return f.err().LogValue()
+}
+
+// Is reports whether any Value of the tree matches target using [errors.Is],
+// or whether target is one of the errors of the tree, see [Err.Is].
+func (f *Frozen) Is(err error) bool {
+	if target, ok := err.(*Frozen); ok && target != nil {
+		err = target.e
+	}
+	return f.err().Is(err)
+}
+
+// As finds the first Value of the tree that matches target using
+// [errors.As], see [Err.As].
+func (f *Frozen) As(target any) bool {
+	return f.err().As(target)
+}
+
+// Unwrap returns the Value, the Prev error and the Causes of the receiver,
+// skipping nil ones. The Prev error and the Causes are not returned as *Err,
+// so that [errors.As] cannot expose a mutable *Err of the tree, but as errors
+// wrapping them, which match only their own error in [errors.Is] and
+// [errors.As], so that the standard library walks the tree in linear time.
+// They format and print like the *Frozen they wrap; use [errors.As] with a
+// target of type *Frozen to get it.
+func (f *Frozen) Unwrap() []error {
+	if f == nil {
+		return nil
+	}
+	return unwrapFrozen(f.e)
+}
+
+// unwrapFrozen implements [Frozen.Unwrap] for the frozen error e.
+func unwrapFrozen(e *Err) []error {
+	var errs []error
+	if e.Value != nil {
+		errs = append(errs, e.Value)
+	}
+	if e.Prev != nil {
+		errs = append(errs, &frozenLink{e: e.Prev})
+	}
+	for _, cause := range e.Causes {
+		if cause != nil {
+			errs = append(errs, &frozenLink{e: cause})
+		}
+	}
+	return errs
+}
+
+// frozenLink is an error of the tree returned by [Frozen.Unwrap], the frozen
+// counterpart of treeLink. A frozen tree is never cyclic.
+type frozenLink struct {
+	e *Err
+}
+
+// Error implements the error interface, see [Frozen.Error].
+func (l *frozenLink) Error() string {
+	return frozen(l.e).Error()
+}
+
+// Format implements [fmt.Formatter], see [Frozen.Format].
+func (l *frozenLink) Format(s fmt.State, verb rune) {
+	frozen(l.e).Format(s, verb)
+}
+
+// Is reports whether target is the wrapped error. Its Value is matched by
+// [errors.Is] through [frozenLink.Unwrap].
+func (l *frozenLink) Is(err error) bool {
+	if target, ok := err.(*Frozen); ok && target != nil {
+		return target.e == l.e
+	}
+	return error(l.e) == err
+}
+
+// As sets target to the wrapped error if target is a *Frozen. Its Value is
+// matched by [errors.As] through [frozenLink.Unwrap].
+func (l *frozenLink) As(target any) bool {
+	if p, ok := target.(**Frozen); ok {
+		*p = frozen(l.e)
+		return true
+	}
+	return false
+}
+
+// Unwrap returns the Value, the Prev error and the Causes of the wrapped
+// error.
+func (l *frozenLink) Unwrap() []error {
+	return unwrapFrozen(l.e)
+}
+
+// Eq reports whether f and other have the same Value and an identical tree,
+// see [Err.Eq].
+func (f *Frozen) Eq(other *Frozen) bool {
+	return f.err().Eq(other.err())
+}
+
+// MarshalJSON implements [json.Marshaler], see [Err.MarshalJSON].
+func (f *Frozen) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.err())
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], see [Err.UnmarshalJSON]. It
+// replaces the receiver, so it must only be called on a *Frozen that is not
+// shared yet.
+func (f *Frozen) UnmarshalJSON(data []byte) error {
+	var e Err
+	if err := json.Unmarshal(data, &e); err != nil {
+		return err
+	}
+
+	f.e = &e
+	return nil
+}
@@ -0,0 +1,409 @@
+package xerr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestFrozen returns a frozen chain of two errors with a cause.
+func newTestFrozen() *Frozen {
+	return Make(errFetchFailed,
+		WithMsg("cannot fetch user"),
+		WithCode(500),
+		WithDetails(map[string]int{"user_id": 42}),
+		WithPrev(NewSimple(errDNS, "dns error", nil)),
+		WithCauses(NewSimple(errTimeout, "profile service", nil)),
+	).Freeze()
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Freeze() and Thaw()
+//
+// ----------------------------------------------------------------------------
+
+func TestErr_Freeze(t *testing.T) {
+	e := New(errFetchFailed, "cannot fetch user", nil, 500, NewSimple(errDNS, "dns error", nil))
+	f := e.Freeze()
+
+	e.Msg = "changed"
+	e.Prev.Msg = "changed"
+
+	assert.Equal(t, "cannot fetch user", f.Msg())
+	assert.Equal(t, "dns error", f.Prev().Msg())
+}
+
+func TestErr_Freeze_Nil(t *testing.T) {
+	var e *Err
+	assert.Nil(t, e.Freeze())
+}
+
+func TestFrozen_Thaw(t *testing.T) {
+	f := newTestFrozen()
+	e := f.Thaw()
+
+	e.Msg = "changed"
+	e.Prev.Msg = "changed"
+	e.Causes[0].Msg = "changed"
+
+	assert.Equal(t, "cannot fetch user", f.Msg())
+	assert.Equal(t, "dns error", f.Prev().Msg())
+	assert.Equal(t, "profile service", f.Causes()[0].Msg())
+	assert.True(t, f.Eq(newTestFrozen()))
+
+	var nilFrozen *Frozen
+	assert.Nil(t, nilFrozen.Thaw())
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of the accessors
+//
+// ----------------------------------------------------------------------------
+
+func TestFrozen_Accessors(t *testing.T) {
+	now := time.Now()
+	e := Make(errFetchFailed,
+		WithMsg("cannot fetch user"),
+		WithCode(500),
+		WithDetails(42),
+		WithTimestamp(now),
+		WithStackPolicy(StackAlways),
+		WithPrev(NewSimple(errDNS, "", nil)),
+		WithCauses(NewSimple(errTimeout, "", nil), NewSimple(errRefused, "", nil)),
+	)
+	f := e.Freeze()
+
+	assert.Equal(t, errFetchFailed, f.Value())
+	assert.Equal(t, 500, f.Code())
+	assert.Equal(t, "cannot fetch user", f.Msg())
+	assert.Equal(t, 42, f.Details())
+	assert.Equal(t, e.File, f.File())
+	assert.Equal(t, e.Line, f.Line())
+	assert.Equal(t, now.UnixMicro(), f.Timestamp().UnixMicro())
+	assert.NotSame(t, e.StackTrace, f.StackTrace())
+	assert.Equal(t, e.Frames(), f.StackTrace().Frames())
+	assert.Equal(t, e.Frames(), f.Frames())
+	assert.Equal(t, errDNS, f.Prev().Value())
+	assert.Nil(t, f.Prev().Prev())
+	require.Len(t, f.Causes(), 2)
+	assert.Equal(t, errRefused, f.Causes()[1].Value())
+	assert.Nil(t, f.Prev().Causes())
+}
+
+func TestFrozen_StackTrace_Copy(t *testing.T) {
+	f := Make(errFetchFailed, WithStackPolicy(StackAlways)).Freeze()
+
+	require.NoError(t, json.Unmarshal([]byte(`[{"function":"forged"}]`), f.StackTrace()))
+	assert.NotEqual(t, "forged", f.Frames()[0].Function)
+
+	var decoded Err
+	require.NoError(t, json.Unmarshal([]byte(`{"value":"test","stack_trace":[{"function":"main.main"}]}`), &decoded))
+	assert.Equal(t, []Frame{{Function: "main.main"}}, decoded.Freeze().StackTrace().Frames())
+}
+
+func TestFrozen_Accessors_Nil(t *testing.T) {
+	var f *Frozen
+
+	assert.Nil(t, f.Value())
+	assert.Zero(t, f.Code())
+	assert.Equal(t, SeverityUnspecified, f.Severity())
+	assert.Equal(t, SeverityUnspecified, f.MaxSeverity())
+	assert.Empty(t, f.Msg())
+	assert.Empty(t, f.PublicMsg())
+	assert.Empty(t, f.PublicMessage())
+	assert.Nil(t, f.Details())
+	assert.Empty(t, f.File())
+	assert.Zero(t, f.Line())
+	assert.True(t, f.Timestamp().IsZero())
+	assert.Nil(t, f.StackTrace())
+	assert.Nil(t, f.Frames())
+	assert.Nil(t, f.Prev())
+	assert.Nil(t, f.Causes())
+	assert.Empty(t, f.Error())
+	assert.Nil(t, f.Unwrap())
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of the With* methods and Wrap()
+//
+// ----------------------------------------------------------------------------
+
+func TestFrozen_With(t *testing.T) {
+	f := newTestFrozen()
+	prev := NewSimple(errRefused, "", nil).Freeze()
+	cause := NewSimple(errRequired, "", nil).Freeze()
+
+	g := f.WithValue(errInvalid).
+		WithMsg("changed").
+		WithPublicMsg("public").
+		WithSeverity(SeverityWarn).
+		WithCode(400).
+		WithDetails("details").
+		WithPrev(prev).
+		WithCauses(cause, nil)
+
+	assert.Equal(t, errInvalid, g.Value())
+	assert.Equal(t, "changed", g.Msg())
+	assert.Equal(t, "public", g.PublicMsg())
+	assert.Equal(t, "public", g.PublicMessage())
+	assert.Equal(t, SeverityWarn, g.Severity())
+	assert.Equal(t, SeverityWarn, g.MaxSeverity())
+	assert.Equal(t, 400, g.Code())
+	assert.Equal(t, "details", g.Details())
+	assert.True(t, g.Prev().Eq(prev))
+	require.Len(t, g.Causes(), 1)
+	assert.True(t, g.Causes()[0].Eq(cause))
+	assert.Equal(t, f.File(), g.File())
+
+	assert.True(t, f.Eq(newTestFrozen()))
+	assert.Equal(t, "cannot fetch user", f.Msg())
+	assert.Equal(t, 500, f.Code())
+}
+
+func TestFrozen_With_SharesChain(t *testing.T) {
+	f := newTestFrozen()
+	g := f.WithMsg("changed")
+
+	assert.Same(t, f.e.Prev, g.e.Prev)
+	assert.Same(t, f.e.Causes[0], g.e.Causes[0])
+}
+
+func TestFrozen_With_Nil(t *testing.T) {
+	var f *Frozen
+
+	assert.Nil(t, f.WithMsg("changed"))
+	assert.Nil(t, f.WithCauses())
+}
+
+func TestFrozen_Wrap(t *testing.T) {
+	f := newTestFrozen()
+	wrapped := f.Wrap(errors.New("handler failed"), WithMsg("request failed"), WithCode(503))
+	_, file, line, _ := runtime.Caller(0)
+
+	assert.Equal(t, "request failed", wrapped.Msg())
+	assert.Equal(t, 503, wrapped.Code())
+	assert.Same(t, f.e, wrapped.e.Prev)
+	assert.Equal(t, file, wrapped.File())
+	assert.Equal(t, line-1, wrapped.Line())
+	assert.True(t, errors.Is(wrapped, errTimeout))
+}
+
+func TestFrozen_Wrap_IgnoresWithPrev(t *testing.T) {
+	f := newTestFrozen()
+	wrapped := f.Wrap(errors.New("handler failed"), WithPrev(NewSimple(errRequired, "", nil)))
+
+	assert.Same(t, f.e, wrapped.e.Prev)
+}
+
+func TestFrozen_Wrap_StackRootOnly(t *testing.T) {
+	f := Make(errDNS, WithStackPolicy(StackAlways)).Freeze()
+	wrapped := f.Wrap(errFetchFailed, WithStackPolicy(StackRootOnly))
+
+	assert.Nil(t, wrapped.StackTrace())
+}
+
+func TestFrozen_Wrap_Nil(t *testing.T) {
+	assert.Nil(t, newTestFrozen().Wrap(nil))
+
+	var f *Frozen
+	wrapped := f.Wrap(errFetchFailed)
+	assert.Equal(t, errFetchFailed, wrapped.Value())
+	assert.Nil(t, wrapped.Prev())
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of the error methods
+//
+// ----------------------------------------------------------------------------
+
+func TestFrozen_Error(t *testing.T) {
+	f := newTestFrozen()
+	e := f.Thaw()
+
+	assert.Equal(t, e.Error(), f.Error())
+	assert.Equal(t, fmt.Sprintf("%v", e), fmt.Sprintf("%v", f))
+	assert.Equal(t, fmt.Sprintf("%+v", e), fmt.Sprintf("%+v", f))
+}
+
+func TestFrozen_LogValue(t *testing.T) {
+	f := newTestFrozen()
+
+	assert.Equal(t, logJSON(t, "error", f.Thaw())["error"], logJSON(t, "error", f)["error"])
+}
+
+func TestFrozen_Is(t *testing.T) {
+	f := newTestFrozen()
+
+	assert.True(t, f.Is(errFetchFailed))
+	assert.True(t, f.Is(errDNS))
+	assert.True(t, f.Is(errTimeout))
+	assert.True(t, f.Is(f.Prev()))
+	assert.False(t, f.Is(fs.ErrNotExist))
+	assert.True(t, errors.Is(f, errTimeout))
+	assert.True(t, errors.Is(f, f.Causes()[0]))
+
+	var nilFrozen *Frozen
+	assert.False(t, f.Is(nilFrozen))
+	assert.False(t, nilFrozen.Is(errDNS))
+}
+
+func TestFrozen_As(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "/tmp/config", Err: fs.ErrNotExist}
+	f := NewSimple(errFetchFailed, "", FromError(pathErr)).Freeze()
+
+	var target *fs.PathError
+	require.True(t, f.As(&target))
+	assert.Equal(t, "/tmp/config", target.Path)
+
+	target = nil
+	require.True(t, errors.As(f, &target))
+	assert.Equal(t, "/tmp/config", target.Path)
+}
+
+func TestFrozen_Unwrap_DoesNotExposeErr(t *testing.T) {
+	f := newTestFrozen()
+
+	var e *Err
+	assert.False(t, errors.As(f, &e))
+
+	var prev *Frozen
+	require.True(t, errors.As(f.Unwrap()[1], &prev))
+	assert.Equal(t, "dns error", prev.Msg())
+	assert.Len(t, f.Unwrap(), 3)
+}
+
+func TestFrozen_Unwrap_Links(t *testing.T) {
+	f := newTestFrozen()
+	errs := f.Unwrap()
+
+	assert.Equal(t, f.Prev().Error(), errs[1].Error())
+	assert.Equal(t, fmt.Sprintf("%+v", f.Prev()), fmt.Sprintf("%+v", errs[1]))
+	assert.True(t, errors.Is(errs[1], f.Prev()))
+	assert.True(t, errors.Is(errs[1], errDNS))
+	assert.False(t, errors.Is(errs[1], errFetchFailed))
+
+	var e *Err
+	assert.False(t, errors.As(errs[1], &e))
+}
+
+func TestFrozen_DeepChain_ErrorsIsAs(t *testing.T) {
+	e := newDeepChain(10_000)
+	e.Root().Value = errRequired
+	f := e.Freeze()
+
+	assert.True(t, f.Is(errRequired))
+	assert.True(t, errors.Is(f, errRequired))
+	assert.False(t, errors.Is(f, fs.ErrNotExist))
+	assert.True(t, errors.Is(f, f.Prev().Prev()))
+}
+
+func TestFrozen_Eq(t *testing.T) {
+	f := newTestFrozen()
+
+	assert.True(t, f.Eq(newTestFrozen()))
+	assert.False(t, f.Eq(f.WithValue(errInvalid)))
+	assert.False(t, f.Eq(nil))
+
+	var nilFrozen *Frozen
+	assert.True(t, nilFrozen.Eq(nil))
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of JSON compatibility
+//
+// ----------------------------------------------------------------------------
+
+func TestFrozen_MarshalJSON(t *testing.T) {
+	f := newTestFrozen()
+
+	got, err := json.Marshal(f)
+	require.NoError(t, err)
+	want, err := json.Marshal(f.Thaw())
+	require.NoError(t, err)
+
+	assert.JSONEq(t, string(want), string(got))
+}
+
+func TestFrozen_UnmarshalJSON(t *testing.T) {
+	e := newTestFrozen().Thaw()
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+
+	var f Frozen
+	require.NoError(t, json.Unmarshal(data, &f))
+
+	assert.Equal(t, e.Msg, f.Msg())
+	assert.Equal(t, e.Line, f.Line())
+	assert.Equal(t, e.Prev.Msg, f.Prev().Msg())
+	assert.Equal(t, e.Causes[0].Msg, f.Causes()[0].Msg())
+
+	var decoded Err
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.True(t, f.Eq(decoded.Freeze()))
+}
+
+func TestFrozen_UnmarshalJSON_Invalid(t *testing.T) {
+	var f Frozen
+	assert.Error(t, json.Unmarshal([]byte(`{"value":1}`), &f))
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of concurrent use, meant to be run with the race detector
+//
+// ----------------------------------------------------------------------------
+
+func TestFrozen_Concurrent(t *testing.T) {
+	f := newTestFrozen()
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Go(func() {
+			for range 100 {
+				_ = f.Error()
+				_ = fmt.Sprintf("%+v", f)
+				_, _ = json.Marshal(f)
+				_ = f.Frames()
+				_ = errors.Is(f, errTimeout)
+				_ = f.Eq(f.Prev())
+			}
+		})
+		wg.Go(func() {
+			for range 100 {
+				wrapped := f.Wrap(fmt.Errorf("attempt %d", i), WithMsg("retry failed"))
+				_ = wrapped.WithCode(i).WithMsg("changed").WithCauses(f, wrapped)
+				_ = f.Thaw().Wrap(errRequired, "", nil, 0)
+			}
+		})
+	}
+	wg.Wait()
+
+	assert.True(t, f.Eq(newTestFrozen()))
+}
+
+func TestFrozen_Concurrent_Logging(t *testing.T) {
+	f := Make(errFetchFailed, WithStackPolicy(StackAlways)).Freeze()
+	setLogStackTrace(t, true)
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			record := logJSON(t, "error", f)
+			assert.NotEmpty(t, record["error"].(map[string]any)["stack_trace"])
+		})
+	}
+	wg.Wait()
+}
@@ -0,0 +1,46 @@
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/fabienbellanger/xerr"
+)
+
+func ExampleWriteProblem() {
+	err := xerr.Make(errors.New("not found"),
+		xerr.WithMsg("no row for user 42"),
+		xerr.WithPublicMsg("User not found"),
+		xerr.WithCode(404),
+	)
+	r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	w := httptest.NewRecorder()
+
+	_ = WriteProblem(w, r, err)
+	fmt.Println(w.Code)
+	fmt.Println(w.Header().Get("Content-Type"))
+	fmt.Println(w.Body.String())
+
+	// Output:
+	// 404
+	// application/problem+json
+	// {"code":404,"detail":"User not found","instance":"/users/42","status":404,"title":"Not Found","type":"about:blank"}
+}
+
+func ExampleHandle() {
+	h := Handle(func(w http.ResponseWriter, r *http.Request) *xerr.Err {
+		return xerr.New(errors.New("not found"), "User not found", nil, 404, nil)
+	}, WithOnError(func(r *http.Request, e *xerr.Err) {
+		fmt.Printf("%s %s: %v\n", r.Method, r.URL.Path, e)
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	fmt.Println(w.Code)
+
+	// Output:
+	// GET /users/42: User not found: not found
+	// 404
+}
@@ -0,0 +1,138 @@
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+
+	"github.com/fabienbellanger/xerr"
+)
+
+// ErrPanic is the error wrapped in the Value of the *xerr.Err created when a
+// panic is recovered.
+var ErrPanic = errors.New("httpx: panic")
+
+// HandlerFunc is an HTTP handler that returns its error instead of writing
+// it. Use [Handle] to adapt it to an [http.Handler].
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) *xerr.Err
+
+// Recoverer returns a middleware recovering the panics of the wrapped handler.
+// A recovered panic is converted to an *xerr.Err (see [Recovered]) and written
+// as the response like the errors returned by a [HandlerFunc].
+//
+// As with [net/http], a panic with [http.ErrAbortHandler] is not recovered.
+//
+// Example:
+//
+//	mux := http.NewServeMux()
+//	http.ListenAndServe(":8080", httpx.Recoverer(httpx.WithOnError(logError))(mux))
+func Recoverer(opts ...Option) func(http.Handler) http.Handler {
+	c := newConfig(opts)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer c.recover(w, r)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Handle adapts h to an [http.Handler]. A non-nil error returned by h is
+// written as a Problem Details response by [WriteProblem] with opts, so that
+// the internal fields of the error are stripped unless [WithInternal] is set.
+// Panics of h are recovered as with [Recoverer].
+//
+// Example:
+//
+//	mux.Handle("GET /users/{id}", httpx.Handle(func(w http.ResponseWriter, r *http.Request) *xerr.Err {
+//		user, err := findUser(r.PathValue("id"))
+//		if err != nil {
+//			return err
+//		}
+//		return writeJSON(w, user)
+//	}))
+func Handle(h HandlerFunc, opts ...Option) http.Handler {
+	c := newConfig(opts)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer c.recover(w, r)
+
+		if e := h(w, r); e != nil {
+			c.writeError(w, r, e)
+		}
+	})
+}
+
+// Recovered converts the value v returned by the builtin recover function
+// into an *xerr.Err whose Value wraps [ErrPanic] and, if v is an error, v. The
+// call site and the stack trace are those of the panicking function. Returns
+// nil if v is nil.
+//
+// Recovered must be called directly by the deferred function that called
+// recover.
+func Recovered(v any) *xerr.Err {
+	if v == nil {
+		return nil
+	}
+
+	value := fmt.Errorf("%w: %v", ErrPanic, v)
+	if err, ok := v.(error); ok {
+		value = fmt.Errorf("%w: %w", ErrPanic, err)
+	}
+
+	return xerr.Make(value,
+		xerr.WithMsg("panic recovered"),
+		xerr.WithCode(http.StatusInternalServerError),
+		xerr.WithSkip(panicSkip()),
+		xerr.WithStackPolicy(xerr.StackAlways),
+	)
+}
+
+// panicSkip returns the skip to pass to [xerr.WithSkip] from [Recovered] so
+// that the call site is the panicking function: the first frame after the
+// runtime panic frames.
+func panicSkip() int {
+	var pcs [64]uintptr
+	n := runtime.Callers(3, pcs[:]) // Callers, panicSkip and Recovered
+	frames := runtime.CallersFrames(pcs[:n])
+
+	panicking := false
+	for i := 0; ; i++ {
+		frame, more := frames.Next()
+		if frame.Function == "runtime.gopanic" {
+			panicking = true
+		} else if panicking && !strings.HasPrefix(frame.Function, "runtime.") {
+			return i + 2 // Make and Recovered
+		}
+		if !more {
+			return 2
+		}
+	}
+}
+
+// recover recovers a panic and writes it as the response. It must be called
+// directly by a deferred function call.
+func (c config) recover(w http.ResponseWriter, r *http.Request) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	if v == http.ErrAbortHandler {
+		panic(v)
+	}
+
+	c.writeError(w, r, Recovered(v))
+}
+
+// writeError calls the error hook and writes e as a Problem Details
+// response.
+func (c config) writeError(w http.ResponseWriter, r *http.Request, e *xerr.Err) {
+	if c.onError != nil {
+		c.onError(r, e)
+	}
+
+	_ = c.writeProblem(w, r, e)
+}
@@ -0,0 +1,258 @@
+package httpx
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fabienbellanger/xerr"
+	"github.com/stretchr/testify/assert"
+)
+
+// errNotFound is a sentinel error used by the test handlers.
+var errNotFound = errors.New("not found")
+
+// decodeBody decodes the JSON problem written in w.
+func decodeBody(t *testing.T, w *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return body
+}
+
+// panickingHandler panics with v.
+func panickingHandler(v any) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(v)
+	})
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Handle()
+//
+// ----------------------------------------------------------------------------
+
+func TestHandle_Success(t *testing.T) {
+	h := Handle(func(w http.ResponseWriter, r *http.Request) *xerr.Err {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("created"))
+		return nil
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users", nil))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "created", w.Body.String())
+}
+
+func TestHandle_Error(t *testing.T) {
+	h := Handle(func(w http.ResponseWriter, r *http.Request) *xerr.Err {
+		return xerr.New(errNotFound, "User not found", nil, 404, nil)
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+
+	body := decodeBody(t, w)
+	assert.Equal(t, float64(404), body["status"])
+	assert.Equal(t, float64(404), body["code"])
+	assert.Equal(t, "/users/42", body["instance"])
+	for _, key := range []string{"value", "msg", "detail", "file", "line", "stack_trace", "error"} {
+		assert.NotContains(t, body, key)
+	}
+}
+
+func TestHandle_Error_Wrapped(t *testing.T) {
+	h := func(w http.ResponseWriter, r *http.Request) *xerr.Err {
+		inner := xerr.Make(errNotFound, xerr.WithMsg("no row in users"), xerr.WithStackPolicy(xerr.StackAlways))
+		return inner.Wrap(errors.New("lookup failed"), "User not found", nil, 404)
+	}
+
+	w := httptest.NewRecorder()
+	Handle(h).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	for _, leak := range []string{"stack_trace", "middleware_test.go", `"line"`, "no row in users", "lookup failed", "User not found"} {
+		assert.NotContains(t, w.Body.String(), leak)
+	}
+
+	w = httptest.NewRecorder()
+	Handle(h, WithInternal()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	internal := decodeBody(t, w)["error"].(map[string]any)
+	assert.Equal(t, "no row in users", internal["prev"].(map[string]any)["msg"])
+	assert.NotContains(t, w.Body.String(), "stack_trace")
+}
+
+func TestHandle_StatusMapping(t *testing.T) {
+	h := Handle(func(w http.ResponseWriter, r *http.Request) *xerr.Err {
+		return xerr.New(errNotFound, "User not found", nil, 1001, nil)
+	}, WithStatus(1001, http.StatusGone))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	assert.Equal(t, http.StatusGone, w.Code)
+}
+
+func TestHandle_OnError(t *testing.T) {
+	var gotRequest *http.Request
+	var gotErr *xerr.Err
+	h := Handle(func(w http.ResponseWriter, r *http.Request) *xerr.Err {
+		return xerr.New(errNotFound, "User not found", nil, 404, nil)
+	}, WithOnError(func(r *http.Request, e *xerr.Err) {
+		gotRequest, gotErr = r, e
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	assert.Same(t, r, gotRequest)
+	assert.True(t, gotErr.Is(errNotFound))
+}
+
+func TestHandle_WithStackTrace(t *testing.T) {
+	h := Handle(func(w http.ResponseWriter, r *http.Request) *xerr.Err {
+		return xerr.New(errNotFound, "User not found", nil, 404, nil)
+	}, WithStackTrace())
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	internal := decodeBody(t, w)["error"].(map[string]any)
+	assert.NotEmpty(t, internal["stack_trace"])
+}
+
+func TestHandle_Redacted(t *testing.T) {
+	type login struct {
+		Email string `json:"email" xerr:"redact"`
+	}
+	h := Handle(func(w http.ResponseWriter, r *http.Request) *xerr.Err {
+		return xerr.New(errNotFound, "User not found", login{Email: "bob@example.com"}, 404, nil)
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	assert.NotContains(t, w.Body.String(), "bob@example.com")
+
+	w = httptest.NewRecorder()
+	Handle(func(w http.ResponseWriter, r *http.Request) *xerr.Err {
+		return xerr.New(errNotFound, "User not found", login{Email: "bob@example.com"}, 404, nil)
+	}, WithInternal()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	internal := decodeBody(t, w)["error"].(map[string]any)
+	assert.Equal(t, map[string]any{"email": xerr.RedactedValue}, internal["details"])
+}
+
+func TestHandle_Panic(t *testing.T) {
+	h := Handle(func(w http.ResponseWriter, r *http.Request) *xerr.Err {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, "Internal Server Error", decodeBody(t, w)["title"])
+	assert.NotContains(t, w.Body.String(), "boom")
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Recoverer()
+//
+// ----------------------------------------------------------------------------
+
+func TestRecoverer_NoPanic(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+
+	w := httptest.NewRecorder()
+	Recoverer()(next).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "ok", w.Body.String())
+}
+
+func TestRecoverer_Panic(t *testing.T) {
+	var gotErr *xerr.Err
+	h := Recoverer(WithOnError(func(r *http.Request, e *xerr.Err) {
+		gotErr = e
+	}))(panickingHandler("boom"))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	assert.NotContains(t, w.Body.String(), "boom")
+	assert.NotContains(t, w.Body.String(), "stack_trace")
+
+	assert.True(t, gotErr.Is(ErrPanic))
+	assert.True(t, strings.HasSuffix(gotErr.File, "middleware_test.go"))
+	assert.True(t, strings.HasSuffix(gotErr.Frames()[0].Function, "panickingHandler.func1"))
+}
+
+func TestRecoverer_PanicWithError(t *testing.T) {
+	var gotErr *xerr.Err
+	h := Recoverer(WithOnError(func(r *http.Request, e *xerr.Err) {
+		gotErr = e
+	}))(panickingHandler(errNotFound))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.True(t, errors.Is(gotErr, ErrPanic))
+	assert.True(t, errors.Is(gotErr, errNotFound))
+}
+
+func TestRecoverer_RuntimePanic(t *testing.T) {
+	var gotErr *xerr.Err
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var m map[string]int
+		m["boom"]++
+	})
+	h := Recoverer(WithOnError(func(r *http.Request, e *xerr.Err) {
+		gotErr = e
+	}))(next)
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.True(t, strings.HasSuffix(gotErr.File, "middleware_test.go"))
+	assert.True(t, strings.HasSuffix(gotErr.Frames()[0].Function, "TestRecoverer_RuntimePanic.func1"))
+}
+
+func TestRecoverer_AbortHandler(t *testing.T) {
+	h := Recoverer()(panickingHandler(http.ErrAbortHandler))
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Recovered()
+//
+// ----------------------------------------------------------------------------
+
+func TestRecovered_Nil(t *testing.T) {
+	assert.Nil(t, Recovered(nil))
+}
+
+func TestRecovered_WithoutPanic(t *testing.T) {
+	e := Recovered("not a panic")
+
+	assert.True(t, e.Is(ErrPanic))
+	assert.True(t, strings.HasSuffix(e.File, "middleware_test.go"))
+}
@@ -0,0 +1,126 @@
+package httpx
+
+import (
+	"maps"
+	"net/http"
+
+	"github.com/fabienbellanger/xerr"
+)
+
+// Option configures how an *xerr.Err is rendered as an HTTP response.
+type Option func(*config)
+
+// config holds the settings collected from the [Option] values.
+type config struct {
+	statuses   map[int]int
+	statusFunc func(e *xerr.Err) int
+	typeFunc   func(e *xerr.Err) string
+	internal   bool
+	onError    func(r *http.Request, e *xerr.Err)
+	stackTrace bool
+}
+
+// newConfig returns the configuration built from opts.
+func newConfig(opts []Option) config {
+	c := config{statuses: make(map[int]int)}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// WithStatus maps the error code to the HTTP status.
+//
+// The status of an error is resolved in the following order: the function
+// set with [WithStatusFunc], the table built with WithStatus and
+// [WithStatusMap], the status of the code in the default catalog (see
+// [xerr.SetDefaultCatalog]), the Code itself if it is a valid HTTP error
+// status (4xx or 5xx), and finally 500 Internal Server Error.
+func WithStatus(code, status int) Option {
+	return func(c *config) {
+		c.statuses[code] = status
+	}
+}
+
+// WithStatusMap maps each error code of statuses to its HTTP status. See
+// [WithStatus] for the status resolution order.
+func WithStatusMap(statuses map[int]int) Option {
+	return func(c *config) {
+		maps.Copy(c.statuses, statuses)
+	}
+}
+
+// WithStatusFunc sets a function returning the HTTP status of an error. A
+// returned status of 0 falls back to the other resolution rules described in
+// [WithStatus].
+func WithStatusFunc(f func(e *xerr.Err) int) Option {
+	return func(c *config) {
+		c.statusFunc = f
+	}
+}
+
+// WithTypeFunc sets a function returning the problem type URI of an error. An
+// empty type falls back to [DefaultType].
+func WithTypeFunc(f func(e *xerr.Err) string) Option {
+	return func(c *config) {
+		c.typeFunc = f
+	}
+}
+
+// WithInternal includes the internal fields of the error, as encoded by
+// [xerr.Err.JSONFor] for [xerr.OutputClient], in the "error" extension member.
+// It must only be used in development or for trusted clients.
+func WithInternal() Option {
+	return func(c *config) {
+		c.internal = true
+	}
+}
+
+// WithOnError sets a hook called with each error before it is written by
+// [Handle] or [Recoverer], e.g. to log it.
+func WithOnError(f func(r *http.Request, e *xerr.Err)) Option {
+	return func(c *config) {
+		c.onError = f
+	}
+}
+
+// WithStackTrace includes the internal fields of the error with the stack
+// traces of its tree in the "error" extension member, see [WithInternal]. It
+// must only be used in development.
+func WithStackTrace() Option {
+	return func(c *config) {
+		c.internal, c.stackTrace = true, true
+	}
+}
+
+// status returns the HTTP status of e.
+func (c config) status(e *xerr.Err) int {
+	if e == nil {
+		return http.StatusInternalServerError
+	}
+	if c.statusFunc != nil {
+		if status := c.statusFunc(e); status != 0 {
+			return status
+		}
+	}
+	if status, ok := c.statuses[e.Code]; ok {
+		return status
+	}
+	if info, ok := xerr.DefaultCatalog().Lookup(e.Code); ok {
+		return info.Status()
+	}
+	if e.Code >= 400 && e.Code <= 599 {
+		return e.Code
+	}
+	return http.StatusInternalServerError
+}
+
+// problemType returns the problem type URI of e.
+func (c config) problemType(e *xerr.Err) string {
+	if c.typeFunc != nil && e != nil {
+		if t := c.typeFunc(e); t != "" {
+			return t
+		}
+	}
+	return DefaultType
+}
@@ -0,0 +1,134 @@
+// Package httpx renders *xerr.Err as HTTP responses.
+//
+// Errors are rendered as RFC 9457 Problem Details (application/problem+json).
+// Internal fields of the error (value, source, stack trace, ...) are stripped
+// by default so they never reach clients.
+package httpx
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+
+	"github.com/fabienbellanger/xerr"
+)
+
+// ContentType is the media type of RFC 9457 Problem Details in JSON.
+const ContentType = "application/problem+json"
+
+// DefaultType is the problem type used when no type is configured. As per
+// RFC 9457, the title of such a problem is the HTTP status phrase.
+const DefaultType = "about:blank"
+
+// Problem is an RFC 9457 Problem Details object.
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+
+	// Extensions are additional members serialized at the top level of the
+	// problem object. Members with the name of a standard member are ignored.
+	Extensions map[string]any `json:"-"`
+}
+
+// MarshalJSON implements [json.Marshaler], flattening Extensions into the
+// problem object.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type Alias Problem // Use an alias to avoid infinite recursion
+
+	if len(p.Extensions) == 0 {
+		return json.Marshal(Alias(p))
+	}
+
+	standard, err := json.Marshal(Alias(p))
+	if err != nil {
+		return nil, err
+	}
+
+	members := make(map[string]any, len(p.Extensions)+5)
+	maps.Copy(members, p.Extensions)
+	if err := json.Unmarshal(standard, &members); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(members)
+}
+
+// NewProblem builds the Problem describing e. The request r, which may be nil,
+// provides the instance member.
+//
+// By default, the type is [DefaultType], the status is derived from the Code
+// of e (see [WithStatus]), the detail is the public message of e (see
+// [xerr.Err.PublicMessage]), or the message of its code in the default
+// catalog if the chain has none, and the only extension members are the code
+// of e, if not zero, and its symbolic name, if declared in the default
+// catalog. The internal Msg of e is never used as the detail. A nil e is
+// described as an internal server error.
+func NewProblem(e *xerr.Err, r *http.Request, opts ...Option) Problem {
+	return newConfig(opts).problem(e, r)
+}
+
+// problem implements [NewProblem].
+func (c config) problem(e *xerr.Err, r *http.Request) Problem {
+	status := c.status(e)
+
+	p := Problem{
+		Type:   c.problemType(e),
+		Title:  http.StatusText(status),
+		Status: status,
+	}
+	if r != nil && r.URL != nil {
+		p.Instance = r.URL.Path
+	}
+	if e == nil {
+		return p
+	}
+
+	info, declared := xerr.DefaultCatalog().Lookup(e.Code)
+	p.Detail = e.PublicMessage()
+	if p.Detail == "" && declared {
+		p.Detail = info.Message
+	}
+
+	extensions := make(map[string]any)
+	if e.Code != 0 {
+		extensions["code"] = e.Code
+	}
+	if declared {
+		extensions["code_name"] = info.Name
+	}
+	if c.internal {
+		if data, err := e.JSONFor(xerr.OutputClient, c.stackTrace); err == nil && len(data) > 0 {
+			extensions["error"] = json.RawMessage(data)
+		}
+	}
+	if len(extensions) > 0 {
+		p.Extensions = extensions
+	}
+
+	return p
+}
+
+// WriteProblem writes the Problem describing e to w, with the
+// [ContentType] content type and the status of the problem.
+func WriteProblem(w http.ResponseWriter, r *http.Request, e *xerr.Err, opts ...Option) error {
+	return newConfig(opts).writeProblem(w, r, e)
+}
+
+// writeProblem implements [WriteProblem].
+func (c config) writeProblem(w http.ResponseWriter, r *http.Request, e *xerr.Err) error {
+	p := c.problem(e, r)
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+	_, err = w.Write(body)
+
+	return err
+}
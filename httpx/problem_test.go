@@ -0,0 +1,253 @@
+package httpx
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/fabienbellanger/xerr"
+	"github.com/stretchr/testify/assert"
+)
+
+// ----------------------------------------------------------------------------
+//
+// Tests of NewProblem()
+//
+// ----------------------------------------------------------------------------
+
+func TestNewProblem(t *testing.T) {
+	e := xerr.New(errors.New("not found"), "User not found", map[string]int{"id": 42}, 404, nil)
+	r := httptest.NewRequest(http.MethodGet, "/users/42?expand=true", nil)
+
+	p := NewProblem(e, r)
+
+	assert.Equal(t, Problem{
+		Type:       DefaultType,
+		Title:      "Not Found",
+		Status:     http.StatusNotFound,
+		Instance:   "/users/42",
+		Extensions: map[string]any{"code": 404},
+	}, p)
+}
+
+func TestNewProblem_Status(t *testing.T) {
+	tests := []struct {
+		name string
+		code int
+		opts []Option
+		want int
+	}{
+		{name: "zero code", code: 0, want: http.StatusInternalServerError},
+		{name: "http status code", code: 409, want: http.StatusConflict},
+		{name: "non error http status code", code: 302, want: http.StatusInternalServerError},
+		{name: "unknown code", code: 1001, want: http.StatusInternalServerError},
+		{name: "mapped code", code: 1001, opts: []Option{WithStatus(1001, 422)}, want: 422},
+		{
+			name: "status map",
+			code: 1002,
+			opts: []Option{WithStatusMap(map[int]int{1001: 422, 1002: 503})},
+			want: 503,
+		},
+		{name: "mapped http status code", code: 404, opts: []Option{WithStatus(404, 410)}, want: 410},
+		{
+			name: "status func",
+			code: 1001,
+			opts: []Option{WithStatus(1001, 422), WithStatusFunc(func(*xerr.Err) int { return 418 })},
+			want: 418,
+		},
+		{
+			name: "status func fallback",
+			code: 1001,
+			opts: []Option{WithStatus(1001, 422), WithStatusFunc(func(*xerr.Err) int { return 0 })},
+			want: 422,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := xerr.New(errors.New("test"), "", nil, tt.code, nil)
+			p := NewProblem(e, nil, tt.opts...)
+
+			assert.Equal(t, tt.want, p.Status)
+			assert.Equal(t, http.StatusText(tt.want), p.Title)
+		})
+	}
+}
+
+func TestNewProblem_TypeFunc(t *testing.T) {
+	e := xerr.New(errors.New("test"), "", nil, 404, nil)
+
+	p := NewProblem(e, nil, WithTypeFunc(func(e *xerr.Err) string {
+		return "https://example.com/problems/not-found"
+	}))
+	assert.Equal(t, "https://example.com/problems/not-found", p.Type)
+
+	p = NewProblem(e, nil, WithTypeFunc(func(e *xerr.Err) string { return "" }))
+	assert.Equal(t, DefaultType, p.Type)
+}
+
+func TestNewProblem_StripsInternalFields(t *testing.T) {
+	prev := xerr.New(errors.New("connection refused"), "db error", nil, 0, nil)
+	e := xerr.New(errors.New("query failed"), "Cannot fetch user", "secret details", 0, prev)
+
+	data, err := json.Marshal(NewProblem(e, nil))
+	assert.NoError(t, err)
+
+	body := string(data)
+	assert.NotContains(t, body, "query failed")
+	assert.NotContains(t, body, "connection refused")
+	assert.NotContains(t, body, "secret details")
+	assert.NotContains(t, body, "problem_test.go")
+	assert.NotContains(t, body, "stack_trace")
+}
+
+func TestNewProblem_WithInternal(t *testing.T) {
+	e := xerr.New(errors.New("query failed"), "Cannot fetch user", nil, 0, nil)
+
+	p := NewProblem(e, nil, WithInternal())
+
+	internal, err := e.JSON()
+	assert.NoError(t, err)
+	assert.Equal(t, json.RawMessage(internal), p.Extensions["error"])
+}
+
+func TestNewProblem_WithoutPublicMessage(t *testing.T) {
+	e := xerr.New(errors.New("query failed"), "no row for user 42", nil, 404, nil)
+
+	p := NewProblem(e, nil)
+
+	assert.Empty(t, p.Detail)
+	data, err := json.Marshal(p)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "no row for user 42")
+}
+
+func TestNewProblem_PublicMessage(t *testing.T) {
+	prev := xerr.Make(errors.New("not found"), xerr.WithMsg("no row for user 42"), xerr.WithPublicMsg("User not found"))
+	e := xerr.Make(errors.New("query failed"), xerr.WithMsg("Cannot fetch user"), xerr.WithPrev(prev))
+
+	p := NewProblem(e, nil)
+
+	assert.Equal(t, "User not found", p.Detail)
+}
+
+func TestNewProblem_Redacted(t *testing.T) {
+	type login struct {
+		Email string `json:"email" xerr:"redact"`
+	}
+	prev := xerr.SetRedactor(xerr.RedactorFunc(func(msg string, details any, out xerr.Output) (string, any) {
+		if out == xerr.OutputClient {
+			msg = "Cannot log in"
+		}
+		return xerr.TagRedactor{}.Redact(msg, details, out)
+	}))
+	defer xerr.SetRedactor(prev)
+
+	e := xerr.New(errors.New("login failed"), "Cannot log in bob@example.com", login{Email: "bob@example.com"}, 0, nil)
+
+	p := NewProblem(e, nil, WithInternal())
+
+	assert.Empty(t, p.Detail)
+	assert.NotContains(t, string(p.Extensions["error"].(json.RawMessage)), "bob@example.com")
+}
+
+func TestNewProblem_Nil(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/users", nil)
+
+	p := NewProblem(nil, r, WithTypeFunc(func(e *xerr.Err) string { return e.Msg }))
+
+	assert.Equal(t, Problem{
+		Type:     DefaultType,
+		Title:    "Internal Server Error",
+		Status:   http.StatusInternalServerError,
+		Instance: "/users",
+	}, p)
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Problem.MarshalJSON()
+//
+// ----------------------------------------------------------------------------
+
+func TestProblem_MarshalJSON(t *testing.T) {
+	p := Problem{Type: DefaultType, Title: "Not Found", Status: 404}
+
+	data, err := json.Marshal(p)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"about:blank","title":"Not Found","status":404}`, string(data))
+}
+
+func TestProblem_MarshalJSON_Extensions(t *testing.T) {
+	p := Problem{
+		Type:       DefaultType,
+		Title:      "Not Found",
+		Status:     404,
+		Extensions: map[string]any{"code": 1001, "status": 500, "title": "ignored"},
+	}
+
+	data, err := json.Marshal(p)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"code":1001,"status":404,"title":"Not Found","type":"about:blank"}`, string(data))
+}
+
+func TestProblem_MarshalJSON_Error(t *testing.T) {
+	p := Problem{Extensions: map[string]any{"channel": make(chan int)}}
+
+	_, err := json.Marshal(p)
+	assert.Error(t, err)
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of WriteProblem()
+//
+// ----------------------------------------------------------------------------
+
+func TestWriteProblem(t *testing.T) {
+	e := xerr.Make(errors.New("not found"),
+		xerr.WithMsg("no row for user 42"),
+		xerr.WithPublicMsg("User not found"),
+		xerr.WithCode(404),
+	)
+	r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	w := httptest.NewRecorder()
+
+	err := WriteProblem(w, r, e)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, ContentType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Not Found",
+		"status": 404,
+		"detail": "User not found",
+		"instance": "/users/42",
+		"code": 404
+	}`, w.Body.String())
+}
+
+func TestNewProblem_DefaultCatalog(t *testing.T) {
+	catalog := xerr.NewCatalog().MustDefine(
+		xerr.CodeInfo{Code: 1001, Name: "USER_NOT_FOUND", Message: "User not found", Category: xerr.CategoryNotFound},
+		xerr.CodeInfo{Code: 1002, Name: "USER_GONE", HTTPStatus: http.StatusGone},
+	)
+	prev := xerr.SetDefaultCatalog(catalog)
+	t.Cleanup(func() { xerr.SetDefaultCatalog(prev) })
+
+	p := NewProblem(xerr.New(errors.New("not found"), "no row for user 42", nil, 1001, nil), nil)
+	assert.Equal(t, http.StatusNotFound, p.Status)
+	assert.Equal(t, "User not found", p.Detail)
+	assert.Equal(t, "USER_NOT_FOUND", p.Extensions["code_name"])
+
+	p = NewProblem(xerr.New(errors.New("gone"), "", nil, 1002, nil), nil)
+	assert.Equal(t, http.StatusGone, p.Status)
+
+	p = NewProblem(xerr.New(errors.New("gone"), "", nil, 1002, nil), nil, WithStatus(1002, http.StatusNotFound))
+	assert.Equal(t, http.StatusNotFound, p.Status)
+}
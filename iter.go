@@ -0,0 +1,102 @@
+package xerr
+
+import "iter"
+
+// All returns an iterator over the errors of the Prev chain, starting with e
+// and ending with the root error. The Causes of the errors are not visited;
+// use [Err.Walk] to visit the whole tree. If the chain has a cycle, the
+// iteration stops before the first error visited again. The iterator is empty
+// if e is nil.
+//
+// Example:
+//
+//	for link := range err.All() {
+//		fmt.Println(link.Msg)
+//	}
+func (e *Err) All() iter.Seq[*Err] {
+	return func(yield func(*Err) bool) {
+		var p path
+		for link := e; link != nil && p.push(link); link = link.Prev {
+			if !yield(link) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the errors of the Prev chain in reverse
+// order, starting with the root error and ending with e. The iterator is empty
+// if e is nil.
+func (e *Err) Backward() iter.Seq[*Err] {
+	return func(yield func(*Err) bool) {
+		links := make([]*Err, 0, e.Depth())
+		for link := range e.All() {
+			links = append(links, link)
+		}
+
+		for i := len(links) - 1; i >= 0; i-- {
+			if !yield(links[i]) {
+				return
+			}
+		}
+	}
+}
+
+// Filter returns an iterator over the errors of the Prev chain, starting with
+// e, for which match returns true.
+//
+// Example:
+//
+//	for link := range err.Filter(func(link *Err) bool { return link.Code >= 500 }) {
+//		fmt.Println(link.Msg)
+//	}
+func (e *Err) Filter(match func(*Err) bool) iter.Seq[*Err] {
+	return func(yield func(*Err) bool) {
+		for link := range e.All() {
+			if match(link) && !yield(link) {
+				return
+			}
+		}
+	}
+}
+
+// Root returns the root error of the Prev chain, i.e. the deepest cause.
+// Returns e if it has no Prev, or nil if called on a nil pointer.
+func (e *Err) Root() *Err {
+	var root *Err
+	for link := range e.All() {
+		root = link
+	}
+	return root
+}
+
+// Depth returns the number of errors of the Prev chain, including e. Returns 0
+// if called on a nil pointer.
+func (e *Err) Depth() int {
+	depth := 0
+	for range e.All() {
+		depth++
+	}
+	return depth
+}
+
+// Find returns the first error of the Prev chain, starting with e, for which
+// match returns true, or nil if there is none.
+//
+// Example:
+//
+//	notFound := err.Find(func(link *Err) bool { return errors.Is(link.Value, ErrNotFound) })
+func (e *Err) Find(match func(*Err) bool) *Err {
+	for link := range e.Filter(match) {
+		return link
+	}
+	return nil
+}
+
+// FindCode returns the first error of the Prev chain, starting with e, whose
+// Code is code, or nil if there is none.
+func (e *Err) FindCode(code int) *Err {
+	return e.Find(func(link *Err) bool {
+		return link.Code == code
+	})
+}
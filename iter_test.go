@@ -0,0 +1,161 @@
+package xerr
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestChain returns a chain of three errors with the codes 3, 2 and 1, the
+// outermost first.
+func newTestChain() *Err {
+	root := Make(errors.New("root"), WithMsg("root"), WithCode(1))
+	middle := Make(errors.New("middle"), WithMsg("middle"), WithCode(2), WithPrev(root))
+	return Make(errors.New("outer"), WithMsg("outer"), WithCode(3), WithPrev(middle))
+}
+
+// msgs returns the Msg of each error of seq.
+func msgs(seq func(func(*Err) bool)) []string {
+	var all []string
+	for link := range seq {
+		all = append(all, link.Msg)
+	}
+	return all
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of All() and Backward()
+//
+// ----------------------------------------------------------------------------
+
+func TestErr_All(t *testing.T) {
+	assert.Equal(t, []string{"outer", "middle", "root"}, msgs(newTestChain().All()))
+}
+
+func TestErr_All_Break(t *testing.T) {
+	var visited []string
+	for link := range newTestChain().All() {
+		visited = append(visited, link.Msg)
+		if link.Msg == "middle" {
+			break
+		}
+	}
+
+	assert.Equal(t, []string{"outer", "middle"}, visited)
+}
+
+func TestErr_All_SkipsCauses(t *testing.T) {
+	e := Make(errors.New("outer"), WithMsg("outer"), WithCauses(NewSimple(errors.New("cause"), "cause", nil)))
+
+	assert.Equal(t, []string{"outer"}, msgs(e.All()))
+}
+
+func TestErr_All_Nil(t *testing.T) {
+	var e *Err
+	assert.Empty(t, slices.Collect(e.All()))
+}
+
+func TestErr_Backward(t *testing.T) {
+	assert.Equal(t, []string{"root", "middle", "outer"}, msgs(newTestChain().Backward()))
+}
+
+func TestErr_Backward_Break(t *testing.T) {
+	var visited []string
+	for link := range newTestChain().Backward() {
+		visited = append(visited, link.Msg)
+		break
+	}
+
+	assert.Equal(t, []string{"root"}, visited)
+}
+
+func TestErr_Backward_Nil(t *testing.T) {
+	var e *Err
+	assert.Empty(t, slices.Collect(e.Backward()))
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Filter()
+//
+// ----------------------------------------------------------------------------
+
+func TestErr_Filter(t *testing.T) {
+	e := newTestChain()
+
+	assert.Equal(t, []string{"outer", "root"}, msgs(e.Filter(func(link *Err) bool { return link.Code != 2 })))
+	assert.Empty(t, msgs(e.Filter(func(*Err) bool { return false })))
+}
+
+func TestErr_Filter_Break(t *testing.T) {
+	var visited []string
+	for link := range newTestChain().Filter(func(link *Err) bool { return link.Code < 3 }) {
+		visited = append(visited, link.Msg)
+		break
+	}
+
+	assert.Equal(t, []string{"middle"}, visited)
+}
+
+func TestErr_Filter_Nil(t *testing.T) {
+	var e *Err
+	assert.Empty(t, slices.Collect(e.Filter(func(*Err) bool { return true })))
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Root() and Depth()
+//
+// ----------------------------------------------------------------------------
+
+func TestErr_Root(t *testing.T) {
+	e := newTestChain()
+
+	assert.Same(t, e.Prev.Prev, e.Root())
+	assert.Same(t, e.Prev.Prev, e.Prev.Prev.Root())
+}
+
+func TestErr_Root_Nil(t *testing.T) {
+	var e *Err
+	assert.Nil(t, e.Root())
+}
+
+func TestErr_Depth(t *testing.T) {
+	e := newTestChain()
+
+	assert.Equal(t, 3, e.Depth())
+	assert.Equal(t, 1, e.Root().Depth())
+
+	var nilErr *Err
+	assert.Equal(t, 0, nilErr.Depth())
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Find() and FindCode()
+//
+// ----------------------------------------------------------------------------
+
+func TestErr_Find(t *testing.T) {
+	e := newTestChain()
+
+	assert.Same(t, e.Prev, e.Find(func(link *Err) bool { return link.Code < 3 }))
+	assert.Nil(t, e.Find(func(*Err) bool { return false }))
+}
+
+func TestErr_FindCode(t *testing.T) {
+	e := newTestChain()
+
+	assert.Same(t, e, e.FindCode(3))
+	assert.Same(t, e.Prev.Prev, e.FindCode(1))
+	assert.Nil(t, e.FindCode(404))
+}
+
+func TestErr_Find_Nil(t *testing.T) {
+	var e *Err
+	assert.Nil(t, e.Find(func(*Err) bool { return true }))
+	assert.Nil(t, e.FindCode(0))
+}
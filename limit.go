@@ -0,0 +1,105 @@
+package xerr
+
+import "sync/atomic"
+
+// DefaultMaxDepth is the default maximum number of errors of a Prev chain
+// rendered by [Err.Error], [Err.Format], [Err.MarshalJSON] and [Err.LogValue].
+const DefaultMaxDepth = 100
+
+// maxDepth is the maximum number of errors of a Prev chain that are rendered.
+var maxDepth atomic.Int64
+
+func init() {
+	maxDepth.Store(DefaultMaxDepth)
+}
+
+// SetMaxDepth sets the maximum number of errors of a Prev chain rendered by
+// [Err.Error], [Err.Format], [Err.MarshalJSON] and [Err.LogValue], and returns
+// the previous maximum. The errors beyond it are replaced by a truncation
+// marker, e.g. "… 37 more". A depth lower than 1 disables the limit.
+//
+// The limit only applies to the rendering: [Err.Is], [Err.Eq], [Err.Clone] and
+// the iterators always walk the whole chain.
+func SetMaxDepth(depth int) int {
+	if depth < 1 {
+		depth = 0
+	}
+	return int(maxDepth.Swap(int64(depth)))
+}
+
+// truncated reports whether the error at index depth of a Prev chain is
+// beyond the maximum depth set with [SetMaxDepth].
+func truncated(depth int) bool {
+	limit := maxDepth.Load()
+	return limit > 0 && int64(depth) >= limit
+}
+
+// path records the errors from the root of a walk to the current error, so
+// that an error whose Prev or Causes leads back to one of them is detected as
+// a cycle instead of being walked forever. Errors shared by several branches
+// of a tree are not cycles and are walked each time.
+//
+// The first errors are kept in an array to avoid allocating for short chains.
+type path struct {
+	n     int
+	small [16]*Err
+	more  []*Err
+	index map[*Err]struct{}
+}
+
+// push adds e to the path, and reports whether it was not already on it.
+func (p *path) push(e *Err) bool {
+	if p.contains(e) {
+		return false
+	}
+
+	if p.n < len(p.small) {
+		p.small[p.n] = e
+	} else {
+		if p.index == nil {
+			p.index = make(map[*Err]struct{}, 2*len(p.small))
+			for _, link := range p.small {
+				p.index[link] = struct{}{}
+			}
+		}
+		p.more = append(p.more, e)
+	}
+	if p.index != nil {
+		p.index[e] = struct{}{}
+	}
+	p.n++
+
+	return true
+}
+
+// contains reports whether e is on the path.
+func (p *path) contains(e *Err) bool {
+	if p.index != nil {
+		_, ok := p.index[e]
+		return ok
+	}
+
+	for _, link := range p.small[:p.n] {
+		if link == e {
+			return true
+		}
+	}
+	return false
+}
+
+// truncate removes the errors pushed after the first n ones.
+func (p *path) truncate(n int) {
+	for ; p.n > n; p.n-- {
+		var link *Err
+		if p.n > len(p.small) {
+			link = p.more[len(p.more)-1]
+			p.more = p.more[:len(p.more)-1]
+		} else {
+			link = p.small[p.n-1]
+		}
+
+		if p.index != nil {
+			delete(p.index, link)
+		}
+	}
+}
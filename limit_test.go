@@ -0,0 +1,368 @@
+package xerr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setMaxDepth sets the maximum rendered depth for the duration of the test.
+func setMaxDepth(t *testing.T, depth int) {
+	t.Helper()
+
+	prev := SetMaxDepth(depth)
+	t.Cleanup(func() { SetMaxDepth(prev) })
+}
+
+// newDeepChain returns a chain of n errors whose messages are their index,
+// the outermost first.
+func newDeepChain(n int) *Err {
+	var e *Err
+	for i := n - 1; i >= 0; i-- {
+		e = &Err{Value: errors.New("error"), Msg: fmt.Sprint(i), Prev: e}
+	}
+	return e
+}
+
+// newCyclicChain returns a chain of three errors whose root leads back to the
+// second one.
+func newCyclicChain() *Err {
+	e := newDeepChain(3)
+	e.Prev.Prev.Prev = e.Prev
+	return e
+}
+
+// newCyclicTree returns an error with a cause leading back to it.
+func newCyclicTree() *Err {
+	e := &Err{Value: errFetchFailed, Msg: "outer"}
+	e.Causes = []*Err{{Value: errTimeout, Msg: "cause", Prev: e}}
+	return e
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of SetMaxDepth()
+//
+// ----------------------------------------------------------------------------
+
+func TestSetMaxDepth_ReturnsPrevious(t *testing.T) {
+	setMaxDepth(t, 10)
+
+	assert.Equal(t, 10, SetMaxDepth(20))
+	assert.Equal(t, 20, SetMaxDepth(-1))
+	assert.Equal(t, 0, SetMaxDepth(DefaultMaxDepth))
+}
+
+func TestSetMaxDepth_Error(t *testing.T) {
+	setMaxDepth(t, 2)
+
+	e := &Err{Value: errors.New("a"), Prev: &Err{Value: errors.New("b"), Prev: newDeepChain(37)}}
+	assert.Equal(t, "value=a, prev={value=b, prev={… 37 more}}", e.Error())
+}
+
+func TestSetMaxDepth_Format(t *testing.T) {
+	setMaxDepth(t, 2)
+	e := newDeepChain(5)
+
+	assert.Equal(t, "0: 1: … 3 more", fmt.Sprintf("%v", e))
+	assert.True(t, strings.HasSuffix(fmt.Sprintf("%+v", e), "\n[1] error\n    msg: 1\n[2] … 3 more"))
+	assert.Contains(t, fmt.Sprintf("%#v", e), "Prev:(*xerr.Err)(… 3 more)")
+}
+
+func TestSetMaxDepth_MarshalJSON(t *testing.T) {
+	setMaxDepth(t, 2)
+
+	data, err := json.Marshal(newDeepChain(5))
+	require.NoError(t, err)
+
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	prev := raw["prev"].(map[string]any)
+	assert.Equal(t, "1", prev["msg"])
+	assert.Nil(t, prev["prev"])
+	assert.Equal(t, float64(3), prev["more"])
+	assert.NotContains(t, raw, "more")
+}
+
+func TestSetMaxDepth_LogValue(t *testing.T) {
+	setMaxDepth(t, 2)
+
+	record := logJSON(t, "error", newDeepChain(5))
+
+	prev := record["error"].(map[string]any)["prev"].(map[string]any)
+	assert.Equal(t, "1", prev["msg"])
+	assert.NotContains(t, prev, "prev")
+	assert.Equal(t, float64(3), prev["more"])
+}
+
+func TestSetMaxDepth_Disabled(t *testing.T) {
+	setMaxDepth(t, 0)
+	e := newDeepChain(200)
+
+	assert.Equal(t, 199, strings.Count(e.Error(), "prev={"))
+	assert.NotContains(t, e.Error(), "more")
+}
+
+func TestSetMaxDepth_DoesNotLimitWalks(t *testing.T) {
+	setMaxDepth(t, 2)
+	e := newDeepChain(5)
+	e.Root().Value = errRequired
+
+	assert.True(t, e.Is(errRequired))
+	assert.True(t, errors.Is(e, errRequired))
+	assert.Equal(t, 5, e.Depth())
+	assert.Equal(t, 5, e.Clone().Depth())
+	assert.False(t, e.Eq(newDeepChain(5)))
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of cyclic chains
+//
+// ----------------------------------------------------------------------------
+
+func TestErr_Cycle_Iterators(t *testing.T) {
+	e := newCyclicChain()
+
+	assert.Equal(t, []string{"0", "1", "2"}, msgs(e.All()))
+	assert.Equal(t, []string{"2", "1", "0"}, msgs(e.Backward()))
+	assert.Equal(t, 3, e.Depth())
+	assert.Same(t, e.Prev.Prev, e.Root())
+	assert.Nil(t, e.FindCode(404))
+}
+
+func TestErr_Cycle_Walk(t *testing.T) {
+	var visited []string
+	newCyclicTree().Walk(func(link *Err) bool {
+		visited = append(visited, link.Msg)
+		return true
+	})
+
+	assert.Equal(t, []string{"outer", "cause"}, visited)
+}
+
+func TestErr_Cycle_Walk_SharedCause(t *testing.T) {
+	shared := &Err{Value: errTimeout, Msg: "shared"}
+	e := &Err{Value: errFetchFailed, Causes: []*Err{shared, shared}}
+
+	count := 0
+	e.Walk(func(link *Err) bool {
+		if link == shared {
+			count++
+		}
+		return true
+	})
+
+	assert.Equal(t, 2, count)
+	assert.NotContains(t, e.Error(), "cycle")
+}
+
+func TestErr_Cycle_IsAs(t *testing.T) {
+	for _, e := range []*Err{newCyclicChain(), newCyclicTree()} {
+		assert.False(t, e.Is(fs.ErrNotExist))
+		assert.False(t, errors.Is(e, fs.ErrNotExist))
+
+		var pathErr *fs.PathError
+		assert.False(t, e.As(&pathErr))
+		assert.False(t, errors.As(e, &pathErr))
+	}
+
+	e := newCyclicTree()
+	assert.True(t, e.Is(errTimeout))
+	assert.True(t, errors.Is(e, errTimeout))
+	assert.True(t, errors.Is(e, e.Causes[0]))
+}
+
+func TestErr_Cycle_Unwrap(t *testing.T) {
+	e := newCyclicChain()
+
+	errs := e.Unwrap()
+	assert.Len(t, errs, 2)
+	assert.Same(t, e.Prev, unwrapErr(t, errs[1]))
+
+	prev := errs[1].(interface{ Unwrap() []error }).Unwrap()
+	assert.Len(t, prev, 2)
+	root := prev[1].(interface{ Unwrap() []error }).Unwrap()
+	assert.Equal(t, []error{e.Prev.Prev.Value}, root)
+}
+
+func TestErr_Cycle_Clone(t *testing.T) {
+	clone := newCyclicChain().Clone()
+
+	assert.Equal(t, []string{"0", "1", "2"}, msgs(clone.All()))
+	assert.Nil(t, clone.Root().Prev)
+
+	tree := newCyclicTree().Clone()
+	assert.Len(t, tree.Causes, 1)
+	assert.Nil(t, tree.Causes[0].Prev)
+}
+
+func TestErr_Cycle_Eq(t *testing.T) {
+	e := newCyclicChain()
+
+	assert.True(t, e.Eq(e))
+	assert.False(t, e.Eq(e.Clone()))
+	assert.True(t, newCyclicTree().Eq(newCyclicTree()))
+
+	tree := newCyclicTree()
+	assert.True(t, tree.Eq(tree))
+}
+
+func TestErr_Cycle_Error(t *testing.T) {
+	e := newCyclicChain()
+	assert.Equal(t, "value=error, msg=0, prev={value=error, msg=1, prev={value=error, msg=2, prev={… cycle}}}", e.Error())
+
+	tree := newCyclicTree()
+	assert.Equal(t, "value=fetch failed, msg=outer, causes=[{value=timeout, msg=cause, prev={… cycle}}]", tree.Error())
+}
+
+func TestErr_Cycle_Format(t *testing.T) {
+	e := newCyclicChain()
+
+	assert.Equal(t, "0: 1: 2: … cycle", fmt.Sprintf("%v", e))
+	assert.True(t, strings.HasSuffix(fmt.Sprintf("%+v", e), "\n[3] … cycle"))
+	assert.Contains(t, fmt.Sprintf("%#v", e), "Prev:(*xerr.Err)(… cycle)")
+	assert.Equal(t, "outer [cause: … cycle]: fetch failed", fmt.Sprintf("%v", newCyclicTree()))
+}
+
+func TestErr_Cycle_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(newCyclicChain())
+	require.NoError(t, err)
+
+	var decoded Err
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, []string{"0", "1", "2"}, msgs(decoded.All()))
+	assert.Contains(t, string(data), `"cycle":true`)
+
+	data, err = json.Marshal(newCyclicTree())
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"cycle":true`)
+}
+
+func TestErr_Cycle_LogValue(t *testing.T) {
+	record := logJSON(t, "error", newCyclicChain())
+
+	root := record["error"].(map[string]any)["prev"].(map[string]any)["prev"].(map[string]any)
+	assert.Equal(t, "2", root["msg"])
+	assert.Equal(t, true, root["cycle"])
+	assert.NotContains(t, root, "prev")
+
+	record = logJSON(t, "error", newCyclicTree())
+	cause := record["error"].(map[string]any)["causes"].(map[string]any)["0"].(map[string]any)
+	assert.Equal(t, true, cause["cycle"])
+}
+
+func TestErr_Cycle_Multi(t *testing.T) {
+	m := &Multi{Errs: []*Err{newCyclicChain(), newCyclicTree()}}
+
+	assert.Contains(t, m.Error(), "… cycle")
+	assert.Len(t, m.Clone().Errs, 2)
+	assert.False(t, errors.Is(m, fs.ErrNotExist))
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of deep chains
+//
+// ----------------------------------------------------------------------------
+
+func TestErr_DeepChain(t *testing.T) {
+	const depth = 10_000
+	e := newDeepChain(depth)
+	e.Root().Value = errRequired
+
+	assert.Equal(t, depth, e.Depth())
+	assert.True(t, e.Is(errRequired))
+	assert.True(t, errors.Is(e, errRequired))
+	assert.True(t, errors.Is(e, e.Root()))
+	assert.Equal(t, "9999", e.Root().Msg)
+
+	clone := e.Clone()
+	assert.Equal(t, depth, clone.Depth())
+	assert.True(t, e.Eq(clone))
+
+	links := slices.Collect(e.Backward())
+	assert.Len(t, links, depth)
+	assert.Same(t, e, links[depth-1])
+}
+
+func TestErr_DeepChain_ErrorsIsAs(t *testing.T) {
+	const depth = 10_000
+	e := newDeepChain(depth)
+
+	var target *fs.PathError
+	assert.False(t, errors.Is(e, fs.ErrNotExist))
+	assert.False(t, errors.As(e, &target))
+
+	pathErr := &fs.PathError{Op: "open", Path: "/tmp/config", Err: fs.ErrNotExist}
+	e.Root().Value = pathErr
+	assert.True(t, errors.Is(e, fs.ErrNotExist))
+	assert.True(t, errors.As(e, &target))
+	assert.Same(t, pathErr, target)
+}
+
+func TestErr_DeepChain_Rendering(t *testing.T) {
+	e := newDeepChain(10_000)
+
+	more := fmt.Sprintf("… %d more", 10_000-DefaultMaxDepth)
+	assert.Contains(t, e.Error(), more)
+	assert.Contains(t, fmt.Sprintf("%v", e), more)
+	assert.Contains(t, fmt.Sprintf("%+v", e), more)
+	assert.Less(t, len(e.Error()), 10_000)
+
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), fmt.Sprintf(`"more":%d`, 10_000-DefaultMaxDepth))
+
+	var buf strings.Builder
+	slog.New(slog.NewJSONHandler(&buf, nil)).Error("failed", "error", e)
+	assert.Contains(t, buf.String(), fmt.Sprintf(`"more":%d`, 10_000-DefaultMaxDepth))
+}
+
+func TestErr_DeepChain_Unlimited(t *testing.T) {
+	setMaxDepth(t, 0)
+	e := newDeepChain(10_000)
+
+	assert.Equal(t, 9_999, strings.Count(e.Error(), "prev={"))
+	assert.Equal(t, 10_000, strings.Count(fmt.Sprintf("%+v", e), "\n    msg: "))
+
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.Equal(t, 10_000, strings.Count(string(data), `"msg":`))
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of path
+//
+// ----------------------------------------------------------------------------
+
+func TestPath(t *testing.T) {
+	links := slices.Collect(newDeepChain(40).All())
+
+	var p path
+	for _, link := range links {
+		assert.True(t, p.push(link))
+	}
+	assert.False(t, p.push(links[0]))
+	assert.False(t, p.push(links[39]))
+
+	p.truncate(20)
+	assert.True(t, p.contains(links[19]))
+	assert.False(t, p.contains(links[20]))
+	assert.True(t, p.push(links[39]))
+
+	p.truncate(5)
+	assert.True(t, p.contains(links[4]))
+	assert.False(t, p.contains(links[5]))
+	assert.False(t, p.contains(links[39]))
+	assert.Equal(t, 5, p.n)
+}
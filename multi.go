@@ -0,0 +1,197 @@
+package xerr
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Multi aggregates several *Err that happened together, e.g. the failures of
+// the fields of a validation. Unlike Prev, which chains an error to its cause,
+// the errors of a Multi are siblings.
+//
+// Multi implements the multiple errors interface of [errors.Join], so
+// [errors.Is] and [errors.As] walk each of its errors and their chains.
+type Multi struct {
+	Errs []*Err
+}
+
+// Join returns a *Multi aggregating the non-nil errs. Each error is cloned to
+// avoid mutation. Returns nil if every error of errs is nil.
+//
+// Example:
+//
+//	var errs []*Err
+//	if name == "" {
+//		errs = append(errs, NewSimple(ErrRequired, "name is required", nil))
+//	}
+//	if age < 0 {
+//		errs = append(errs, NewSimple(ErrInvalid, "age must be positive", nil))
+//	}
+//	return Join(errs...)
+func Join(errs ...*Err) *Multi {
+	return (*Multi)(nil).Append(errs...)
+}
+
+// Append returns m with the non-nil errs added, cloned to avoid mutation. If
+// m is nil, a new *Multi is returned unless every error of errs is nil, so
+// Append can build a *Multi incrementally:
+//
+//	var m *Multi
+//	for _, field := range fields {
+//		m = m.Append(validate(field))
+//	}
+//	return m
+func (m *Multi) Append(errs ...*Err) *Multi {
+	for _, e := range errs {
+		if e == nil {
+			continue
+		}
+		if m == nil {
+			m = &Multi{}
+		}
+		m.Errs = append(m.Errs, e.Clone())
+	}
+	return m
+}
+
+// Len returns the number of errors of m. Returns 0 if called on a nil
+// pointer.
+func (m *Multi) Len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.Errs)
+}
+
+// Clone creates a deep copy of m, cloning each of its errors with
+// [Err.Clone]. Returns nil if called on a nil pointer.
+func (m *Multi) Clone() *Multi {
+	if m == nil {
+		return nil
+	}
+
+	var errs []*Err
+	if m.Errs != nil {
+		errs = make([]*Err, len(m.Errs))
+		for i, e := range m.Errs {
+			errs[i] = e.Clone()
+		}
+	}
+	return &Multi{Errs: errs}
+}
+
+// Error implements the error interface, returning the [Err.Error] of each
+// non-empty error of m, separated by newlines like [errors.Join].
+func (m *Multi) Error() string {
+	if m == nil {
+		return ""
+	}
+
+	parts := make([]string, 0, len(m.Errs))
+	for _, e := range m.Errs {
+		if !e.IsEmpty() {
+			parts = append(parts, e.Error())
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// Is reports whether target is m or whether any error of m matches target
+// with [Err.Is].
+func (m *Multi) Is(err error) bool {
+	if m == nil {
+		return false
+	}
+	if error(m) == err {
+		return true
+	}
+
+	for _, e := range m.Errs {
+		if e.Is(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error of m whose chain has a Value that matches target
+// with [Err.As], and if one is found, sets target to that error value and
+// returns true. Otherwise, it returns false.
+func (m *Multi) As(target any) bool {
+	if m == nil {
+		return false
+	}
+
+	for _, e := range m.Errs {
+		if e.As(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns the non-nil errors of m. It implements the multiple errors
+// interface walked by [errors.Is] and [errors.As].
+func (m *Multi) Unwrap() []error {
+	if m == nil {
+		return nil
+	}
+
+	errs := make([]error, 0, len(m.Errs))
+	for _, e := range m.Errs {
+		if e != nil {
+			errs = append(errs, e)
+		}
+	}
+	return errs
+}
+
+// Eq reports whether m and other have the same number of errors and whether
+// each pair of errors, in order, is equal with [Err.Eq]. Both nil returns
+// true; one nil returns false.
+func (m *Multi) Eq(other *Multi) bool {
+	if m == nil || other == nil {
+		return m == other
+	}
+	if len(m.Errs) != len(other.Errs) {
+		return false
+	}
+
+	for i, e := range m.Errs {
+		if !e.Eq(other.Errs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ToError returns the receiver as an error interface value, or nil if m is
+// nil or has no error. This avoids the typed-nil pitfall when returning
+// *Multi from a function with an error return type.
+func (m *Multi) ToError() error {
+	if m.Len() == 0 {
+		return nil
+	}
+	return m
+}
+
+// MarshalJSON implements [json.Marshaler], encoding m as a JSON array of its
+// errors, each one encoded with [Err.MarshalJSON].
+func (m *Multi) MarshalJSON() ([]byte, error) {
+	if m == nil || m.Errs == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(m.Errs)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], reversing [Multi.MarshalJSON].
+// Each error is decoded with [Err.UnmarshalJSON].
+func (m *Multi) UnmarshalJSON(data []byte) error {
+	var errs []*Err
+	if err := json.Unmarshal(data, &errs); err != nil {
+		return err
+	}
+
+	m.Errs = errs
+	return nil
+}
@@ -0,0 +1,259 @@
+package xerr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errRequired = errors.New("required")
+	errInvalid  = errors.New("invalid")
+	errNegative = errors.New("negative")
+)
+
+// newTestMulti returns a *Multi with two validation errors.
+func newTestMulti() *Multi {
+	return Join(
+		NewSimple(errRequired, "name is required", nil),
+		NewSimple(errInvalid, "age must be positive", NewSimple(errNegative, "", nil)),
+	)
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Join() and Append()
+//
+// ----------------------------------------------------------------------------
+
+func TestJoin(t *testing.T) {
+	e1 := NewSimple(errRequired, "name is required", nil)
+	e2 := NewSimple(errInvalid, "age must be positive", nil)
+
+	m := Join(e1, nil, e2)
+	require.NotNil(t, m)
+	assert.Equal(t, 2, m.Len())
+	assert.True(t, m.Errs[0].Eq(e1))
+	assert.True(t, m.Errs[1].Eq(e2))
+}
+
+func TestJoin_ClonesErrors(t *testing.T) {
+	e := NewSimple(errRequired, "name is required", nil)
+	m := Join(e)
+
+	e.Msg = "mutated"
+	assert.Equal(t, "name is required", m.Errs[0].Msg)
+}
+
+func TestJoin_Nil(t *testing.T) {
+	assert.Nil(t, Join())
+	assert.Nil(t, Join(nil, nil))
+}
+
+func TestMulti_Append(t *testing.T) {
+	var m *Multi
+	m = m.Append(nil)
+	assert.Nil(t, m)
+
+	m = m.Append(NewSimple(errRequired, "name is required", nil))
+	m = m.Append(nil, NewSimple(errInvalid, "age must be positive", nil))
+	assert.Equal(t, 2, m.Len())
+}
+
+func TestMulti_Len_Nil(t *testing.T) {
+	var m *Multi
+	assert.Equal(t, 0, m.Len())
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Clone()
+//
+// ----------------------------------------------------------------------------
+
+func TestMulti_Clone(t *testing.T) {
+	m := newTestMulti()
+	clone := m.Clone()
+
+	assert.True(t, m.Eq(clone))
+	clone.Errs[1].Prev.Msg = "mutated"
+	clone.Errs[0] = nil
+	assert.Equal(t, "name is required", m.Errs[0].Msg)
+	assert.Empty(t, m.Errs[1].Prev.Msg)
+}
+
+func TestMulti_Clone_Nil(t *testing.T) {
+	var m *Multi
+	assert.Nil(t, m.Clone())
+	assert.Nil(t, (&Multi{}).Clone().Errs)
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Error()
+//
+// ----------------------------------------------------------------------------
+
+func TestMulti_Error(t *testing.T) {
+	m := newTestMulti()
+
+	assert.Equal(t, m.Errs[0].Error()+"\n"+m.Errs[1].Error(), m.Error())
+}
+
+func TestMulti_Error_SkipsEmpty(t *testing.T) {
+	m := &Multi{Errs: []*Err{nil, {Value: errRequired}}}
+
+	assert.Equal(t, "value=required", m.Error())
+}
+
+func TestMulti_Error_Nil(t *testing.T) {
+	var m *Multi
+	assert.Empty(t, m.Error())
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Is(), As() and Unwrap()
+//
+// ----------------------------------------------------------------------------
+
+func TestMulti_Is(t *testing.T) {
+	m := newTestMulti()
+
+	assert.True(t, m.Is(errRequired))
+	assert.True(t, m.Is(errInvalid))
+	assert.True(t, m.Is(m))
+	assert.True(t, m.Is(m.Errs[1].Prev))
+	assert.False(t, m.Is(errors.New("required")))
+}
+
+func TestMulti_Is_Nil(t *testing.T) {
+	var m *Multi
+	assert.False(t, m.Is(errRequired))
+}
+
+func TestMulti_ErrorsIs_Compatibility(t *testing.T) {
+	var err error = newTestMulti()
+
+	assert.True(t, errors.Is(err, errRequired))
+	assert.True(t, errors.Is(err, errInvalid))
+	assert.False(t, errors.Is(err, fs.ErrNotExist))
+
+	wrapped := fmt.Errorf("validation: %w", err)
+	assert.True(t, errors.Is(wrapped, errInvalid))
+}
+
+func TestMulti_As(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "/tmp/config", Err: fs.ErrNotExist}
+	m := Join(NewSimple(errRequired, "", nil), NewSimple(pathErr, "cannot read config", nil))
+
+	var target *fs.PathError
+	require.True(t, m.As(&target))
+	assert.Equal(t, "/tmp/config", target.Path)
+
+	target = nil
+	require.True(t, errors.As(m, &target))
+	assert.Equal(t, "/tmp/config", target.Path)
+
+	var linkErr *fs.PathError
+	assert.False(t, Join(NewSimple(errRequired, "", nil)).As(&linkErr))
+}
+
+func TestMulti_As_Nil(t *testing.T) {
+	var m *Multi
+	var target *fs.PathError
+	assert.False(t, m.As(&target))
+}
+
+func TestMulti_Unwrap(t *testing.T) {
+	m := &Multi{Errs: []*Err{{Value: errRequired}, nil, {Value: errInvalid}}}
+
+	errs := m.Unwrap()
+	require.Len(t, errs, 2)
+	assert.Same(t, m.Errs[0], errs[0])
+	assert.Same(t, m.Errs[2], errs[1])
+
+	var nilMulti *Multi
+	assert.Nil(t, nilMulti.Unwrap())
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Eq() and ToError()
+//
+// ----------------------------------------------------------------------------
+
+func TestMulti_Eq(t *testing.T) {
+	m := newTestMulti()
+
+	assert.True(t, m.Eq(newTestMulti()))
+	assert.False(t, m.Eq(Join(m.Errs[0])))
+	assert.False(t, m.Eq(Join(m.Errs[1], m.Errs[0])))
+	assert.False(t, m.Eq(nil))
+
+	var nilMulti *Multi
+	assert.True(t, nilMulti.Eq(nil))
+}
+
+func TestMulti_ToError(t *testing.T) {
+	m := newTestMulti()
+	assert.Equal(t, error(m), m.ToError())
+
+	var nilMulti *Multi
+	assert.Nil(t, nilMulti.ToError())
+	assert.Nil(t, (&Multi{}).ToError())
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of MarshalJSON() and UnmarshalJSON()
+//
+// ----------------------------------------------------------------------------
+
+func TestMulti_MarshalJSON(t *testing.T) {
+	m := newTestMulti()
+
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+
+	var items []map[string]any
+	require.NoError(t, json.Unmarshal(data, &items))
+	require.Len(t, items, 2)
+	assert.Equal(t, "required", items[0]["value"])
+	assert.Equal(t, "name is required", items[0]["msg"])
+	assert.Equal(t, "invalid", items[1]["value"])
+	assert.Equal(t, "negative", items[1]["prev"].(map[string]any)["value"])
+}
+
+func TestMulti_MarshalJSON_Empty(t *testing.T) {
+	data, err := json.Marshal(&Multi{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[]`, string(data))
+}
+
+func TestMulti_UnmarshalJSON(t *testing.T) {
+	m := newTestMulti()
+	data, err := json.Marshal(m)
+	require.NoError(t, err)
+
+	var decoded Multi
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	assert.Equal(t, 2, decoded.Len())
+	assert.Equal(t, "name is required", decoded.Errs[0].Msg)
+	assert.Equal(t, m.Errs[1].Line, decoded.Errs[1].Line)
+	assert.True(t, decoded.Eq(&Multi{Errs: []*Err{
+		{Value: valueError("required")},
+		{Value: valueError("invalid"), Prev: &Err{Value: valueError("negative")}},
+	}}))
+}
+
+func TestMulti_UnmarshalJSON_Invalid(t *testing.T) {
+	var m Multi
+	assert.Error(t, json.Unmarshal([]byte(`{"value": "required"}`), &m))
+}
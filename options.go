@@ -0,0 +1,159 @@
+package xerr
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// Option configures an *Err created by [Make].
+type Option func(*options)
+
+// options holds the settings collected from the [Option] values passed to
+// [Make].
+type options struct {
+	msg        string
+	publicMsg  string
+	details    any
+	tags       Fields
+	code       int
+	severity   Severity
+	prev       *Err
+	sharePrev  bool // prev is immutable and is not cloned, see [Frozen.Wrap]
+	causes     []*Err
+	skip       int
+	stack      StackPolicy
+	timestamp  time.Time
+	retryable  *bool
+	temporary  *bool
+	retryAfter time.Duration
+}
+
+// newOptions returns the options built from opts.
+func newOptions(opts []Option) options {
+	o := options{skip: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithMsg sets the human-readable message of the error.
+func WithMsg(msg string) Option {
+	return func(o *options) {
+		o.msg = msg
+	}
+}
+
+// WithPublicMsg sets the message of the error shown to end users, see
+// [Err.PublicMessage].
+func WithPublicMsg(msg string) Option {
+	return func(o *options) {
+		o.publicMsg = msg
+	}
+}
+
+// WithCode sets the code of the error.
+func WithCode(code int) Option {
+	return func(o *options) {
+		o.code = code
+	}
+}
+
+// WithSeverity sets the severity level of the error, see [Err.MaxSeverity].
+func WithSeverity(s Severity) Option {
+	return func(o *options) {
+		o.severity = s
+	}
+}
+
+// WithDetails sets the arbitrary details attached to the error.
+func WithDetails(details any) Option {
+	return func(o *options) {
+		o.details = details
+	}
+}
+
+// WithFields attaches fields to the error, sorted by key, see [Err.WithFields].
+func WithFields(fields map[string]any) Option {
+	return func(o *options) {
+		for _, key := range slices.Sorted(maps.Keys(fields)) {
+			o.tags = o.tags.set(key, fields[key])
+		}
+	}
+}
+
+// WithPrev chains prev as the previous error. prev is cloned by [Make] so
+// later mutations of prev do not affect the new error.
+func WithPrev(prev *Err) Option {
+	return func(o *options) {
+		o.prev = prev
+	}
+}
+
+// WithCauses sets the causes of the error, e.g. the failures of parallel
+// calls. Nil causes are skipped, and the others are cloned by [Make] so later
+// mutations of them do not affect the new error.
+func WithCauses(causes ...*Err) Option {
+	return func(o *options) {
+		o.causes = causes
+	}
+}
+
+// WithRetryable classifies whether the failed operation is worth retrying,
+// overriding the classification of the Prev chain, see [Retryable].
+func WithRetryable(retryable bool) Option {
+	return func(o *options) {
+		o.retryable = new(retryable)
+	}
+}
+
+// WithTemporary classifies whether the error is caused by a transient
+// condition, overriding the classification of the Prev chain, see
+// [Temporary].
+func WithTemporary(temporary bool) Option {
+	return func(o *options) {
+		o.temporary = new(temporary)
+	}
+}
+
+// WithRetryAfter sets the minimum delay before retrying the failed operation,
+// see [RetryAfter].
+func WithRetryAfter(d time.Duration) Option {
+	return func(o *options) {
+		o.retryAfter = d
+	}
+}
+
+// WithSkip sets the depth passed to [runtime.Caller] for capturing the call
+// site, relative to the constructor the option is passed to, e.g. [Make]. It
+// defaults to 1 (the caller of Make). Wrapper functions should pass a higher
+// value so that File and Line reflect their own caller rather than the
+// wrapper itself.
+func WithSkip(skip int) Option {
+	return func(o *options) {
+		o.skip = skip
+	}
+}
+
+// WithoutStack disables the stack trace capture for the error. It is a
+// shorthand for WithStackPolicy(StackNever).
+func WithoutStack() Option {
+	return WithStackPolicy(StackNever)
+}
+
+// WithStackPolicy overrides the package-level stack capture policy set with
+// [SetStackPolicy] for the error.
+func WithStackPolicy(p StackPolicy) Option {
+	return func(o *options) {
+		o.stack = p
+	}
+}
+
+// WithTimestamp overrides the timestamp of the error, which defaults to the
+// time of its creation.
+func WithTimestamp(t time.Time) Option {
+	return func(o *options) {
+		o.timestamp = t
+	}
+}
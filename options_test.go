@@ -0,0 +1,110 @@
+package xerr
+
+import (
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Make()
+//
+// ----------------------------------------------------------------------------
+
+func TestErr_Make_ReturnsNilOnNilValue(t *testing.T) {
+	assert.Nil(t, Make(nil, WithMsg("My error message")))
+}
+
+func TestErr_Make_Defaults(t *testing.T) {
+	_, _, wantLine, _ := runtime.Caller(0)
+	wantLine += 2
+	err := Make(errors.New("test"))
+
+	assert.Equal(t, errors.New("test"), err.Value)
+	assert.Equal(t, 0, err.Code)
+	assert.Equal(t, "", err.Msg)
+	assert.Nil(t, err.Details)
+	assert.True(t, strings.Contains(err.File, "options_test.go"))
+	assert.Equal(t, wantLine, err.Line)
+	assert.NotZero(t, err.Timestamp)
+	assert.NotEmpty(t, err.StackTrace)
+	assert.Nil(t, err.Prev)
+}
+
+func TestErr_Make_WithOptions(t *testing.T) {
+	details := map[string]int{"user_id": 42}
+	prev := NewSimple(errors.New("root"), "root cause", nil)
+
+	err := Make(errors.New("test"),
+		WithMsg("My error message"),
+		WithCode(404),
+		WithDetails(details),
+		WithPrev(prev),
+	)
+
+	assert.Equal(t, errors.New("test"), err.Value)
+	assert.Equal(t, 404, err.Code)
+	assert.Equal(t, "My error message", err.Msg)
+	assert.Equal(t, details, err.Details)
+	assert.Equal(t, prev, err.Prev)
+}
+
+func TestErr_Make_WithPublicMsg(t *testing.T) {
+	err := Make(errors.New("test"), WithMsg("row 42 not found"), WithPublicMsg("User not found"))
+
+	assert.Equal(t, "row 42 not found", err.Msg)
+	assert.Equal(t, "User not found", err.PublicMsg)
+}
+
+func TestErr_Make_WithPrev_IsCloned(t *testing.T) {
+	prev := NewSimple(errors.New("root"), "root cause", nil)
+	err := Make(errors.New("test"), WithPrev(prev))
+
+	prev.Msg = "changed"
+
+	assert.NotSame(t, prev, err.Prev)
+	assert.Equal(t, "root cause", err.Prev.Msg)
+}
+
+func TestErr_Make_WithCauses(t *testing.T) {
+	cause := NewSimple(errors.New("timeout"), "profile service", nil)
+	err := Make(errors.New("test"), WithCauses(cause, nil, NewSimple(errors.New("refused"), "", nil)))
+
+	cause.Msg = "changed"
+
+	assert.Len(t, err.Causes, 2)
+	assert.NotSame(t, cause, err.Causes[0])
+	assert.Equal(t, "profile service", err.Causes[0].Msg)
+	assert.Nil(t, Make(errors.New("test"), WithCauses(nil)).Causes)
+}
+
+func TestErr_Make_WithSkip(t *testing.T) {
+	err := Make(errors.New("test"), WithSkip(0))
+
+	// skip=0 → runtime.Caller(0) points inside error.go, not at the call site
+	assert.True(t, strings.Contains(err.File, "error.go"))
+}
+
+func TestErr_Make_WithoutStack(t *testing.T) {
+	err := Make(errors.New("test"), WithoutStack())
+
+	assert.Nil(t, err.StackTrace)
+}
+
+func TestErr_Make_WithTimestamp(t *testing.T) {
+	ts := time.Date(2025, 7, 7, 12, 0, 0, 0, time.UTC)
+	err := Make(errors.New("test"), WithTimestamp(ts))
+
+	assert.Equal(t, ts.UnixMicro(), err.Timestamp)
+}
+
+func TestErr_Make_LastOptionWins(t *testing.T) {
+	err := Make(errors.New("test"), WithCode(1), WithCode(2))
+
+	assert.Equal(t, 2, err.Code)
+}
@@ -0,0 +1,92 @@
+package xerr
+
+import (
+	"slices"
+	"sync/atomic"
+)
+
+// StackPolicy decides whether a stack trace is captured when a new *Err is
+// created. It receives the error being built, with all fields but StackTrace
+// already set, and must be safe for concurrent use.
+type StackPolicy func(e *Err) bool
+
+// stackPolicy is the package-level policy used by [Make] when no policy is
+// set with [WithStackPolicy]. A nil value means [StackAlways].
+var stackPolicy atomic.Pointer[StackPolicy]
+
+// SetStackPolicy sets the package-level stack capture policy used by all
+// constructors and returns the previous one. A nil policy restores the
+// default, [StackAlways].
+//
+// Example:
+//
+//	// Only capture stack traces for internal errors
+//	xerr.SetStackPolicy(xerr.StackForCodes(500))
+func SetStackPolicy(p StackPolicy) StackPolicy {
+	var prev *StackPolicy
+	if p == nil {
+		prev = stackPolicy.Swap(nil)
+	} else {
+		prev = stackPolicy.Swap(&p)
+	}
+
+	if prev == nil {
+		return StackAlways
+	}
+	return *prev
+}
+
+// currentStackPolicy returns the package-level stack capture policy.
+func currentStackPolicy() StackPolicy {
+	if p := stackPolicy.Load(); p != nil {
+		return *p
+	}
+	return StackAlways
+}
+
+// StackAlways is a [StackPolicy] that always captures the stack trace. It is
+// the default policy.
+func StackAlways(*Err) bool {
+	return true
+}
+
+// StackNever is a [StackPolicy] that never captures the stack trace.
+func StackNever(*Err) bool {
+	return false
+}
+
+// StackRootOnly is a [StackPolicy] that only captures the stack trace of the
+// root of a chain: it is skipped when an error of the Prev chain or of the
+// Causes already has one.
+func StackRootOnly(e *Err) bool {
+	found := false
+	e.Walk(func(link *Err) bool {
+		found = link != e && link.StackTrace != nil
+		return !found
+	})
+	return !found
+}
+
+// StackForCodes returns a [StackPolicy] that only captures the stack trace of
+// errors whose Code is one of codes.
+func StackForCodes(codes ...int) StackPolicy {
+	codes = slices.Clone(codes)
+
+	return func(e *Err) bool {
+		return slices.Contains(codes, e.Code)
+	}
+}
+
+// StackSampled returns a [StackPolicy] that captures the stack trace of one
+// error out of n, starting with the first one. A value of n lower than 2
+// captures every stack trace.
+func StackSampled(n int) StackPolicy {
+	if n < 2 {
+		return StackAlways
+	}
+
+	var count atomic.Uint64
+	return func(*Err) bool {
+		return (count.Add(1)-1)%uint64(n) == 0
+	}
+}
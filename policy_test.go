@@ -0,0 +1,170 @@
+package xerr
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setStackPolicy sets the package-level stack capture policy for the duration
+// of the test.
+func setStackPolicy(t *testing.T, p StackPolicy) {
+	t.Helper()
+
+	prev := SetStackPolicy(p)
+	t.Cleanup(func() { SetStackPolicy(prev) })
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of SetStackPolicy()
+//
+// ----------------------------------------------------------------------------
+
+func TestSetStackPolicy_Default(t *testing.T) {
+	err := New(errors.New("test"), "My error message", nil, 0, nil)
+	assert.NotNil(t, err.StackTrace)
+}
+
+func TestSetStackPolicy_Never(t *testing.T) {
+	setStackPolicy(t, StackNever)
+
+	assert.Nil(t, New(errors.New("test"), "", nil, 0, nil).StackTrace)
+	assert.Nil(t, NewSimple(errors.New("test"), "", nil).StackTrace)
+	assert.Nil(t, FromError(errors.New("test")).StackTrace)
+
+	prev := NewSimple(errors.New("root"), "", nil)
+	assert.Nil(t, prev.Wrap(errors.New("test"), "", nil, 0).StackTrace)
+}
+
+func TestSetStackPolicy_ReturnsPrevious(t *testing.T) {
+	prev := SetStackPolicy(StackNever)
+	defer SetStackPolicy(prev)
+
+	assert.False(t, SetStackPolicy(StackNever)(&Err{}))
+}
+
+func TestSetStackPolicy_NilRestoresDefault(t *testing.T) {
+	setStackPolicy(t, StackNever)
+
+	old := SetStackPolicy(nil)
+	assert.False(t, old(&Err{}))
+
+	err := New(errors.New("test"), "My error message", nil, 0, nil)
+	assert.NotNil(t, err.StackTrace)
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of WithStackPolicy()
+//
+// ----------------------------------------------------------------------------
+
+func TestWithStackPolicy_OverridesPackagePolicy(t *testing.T) {
+	setStackPolicy(t, StackNever)
+
+	err := Make(errors.New("test"), WithStackPolicy(StackAlways))
+	assert.NotNil(t, err.StackTrace)
+}
+
+func TestWithoutStack_OverridesPackagePolicy(t *testing.T) {
+	setStackPolicy(t, StackAlways)
+
+	err := Make(errors.New("test"), WithoutStack())
+	assert.Nil(t, err.StackTrace)
+}
+
+func TestWithStackPolicy_ReceivesError(t *testing.T) {
+	var got *Err
+	err := Make(errors.New("test"), WithCode(42), WithStackPolicy(func(e *Err) bool {
+		got = e
+		return true
+	}))
+
+	assert.Same(t, err, got)
+	assert.Equal(t, 42, got.Code)
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of StackRootOnly()
+//
+// ----------------------------------------------------------------------------
+
+func TestStackRootOnly(t *testing.T) {
+	setStackPolicy(t, StackRootOnly)
+
+	root := NewSimple(errors.New("root"), "", nil)
+	wrapped := root.Wrap(errors.New("wrapped"), "", nil, 0)
+	wrappedTwice := wrapped.Wrap(errors.New("wrapped twice"), "", nil, 0)
+
+	assert.NotNil(t, root.StackTrace)
+	assert.Nil(t, wrapped.StackTrace)
+	assert.Nil(t, wrappedTwice.StackTrace)
+}
+
+func TestStackRootOnly_PrevWithoutStack(t *testing.T) {
+	root := Make(errors.New("root"), WithoutStack())
+	wrapped := Make(errors.New("wrapped"), WithPrev(root), WithStackPolicy(StackRootOnly))
+
+	assert.NotNil(t, wrapped.StackTrace)
+}
+
+func TestStackRootOnly_Causes(t *testing.T) {
+	cause := Make(errors.New("cause"), WithPrev(Make(errors.New("root"), WithStackPolicy(StackAlways))))
+	withStack := Make(errors.New("wrapped"), WithCauses(cause), WithStackPolicy(StackRootOnly))
+	withoutStack := Make(errors.New("wrapped"), WithCauses(Make(errors.New("cause"), WithoutStack())), WithStackPolicy(StackRootOnly))
+
+	assert.Nil(t, withStack.StackTrace)
+	assert.NotNil(t, withoutStack.StackTrace)
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of StackForCodes()
+//
+// ----------------------------------------------------------------------------
+
+func TestStackForCodes(t *testing.T) {
+	setStackPolicy(t, StackForCodes(500, 503))
+
+	assert.NotNil(t, New(errors.New("test"), "", nil, 500, nil).StackTrace)
+	assert.NotNil(t, New(errors.New("test"), "", nil, 503, nil).StackTrace)
+	assert.Nil(t, New(errors.New("test"), "", nil, 404, nil).StackTrace)
+	assert.Nil(t, New(errors.New("test"), "", nil, 0, nil).StackTrace)
+}
+
+func TestStackForCodes_CopiesCodes(t *testing.T) {
+	codes := []int{500}
+	policy := StackForCodes(codes...)
+	codes[0] = 404
+
+	assert.True(t, policy(&Err{Code: 500}))
+	assert.False(t, policy(&Err{Code: 404}))
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of StackSampled()
+//
+// ----------------------------------------------------------------------------
+
+func TestStackSampled(t *testing.T) {
+	policy := StackSampled(3)
+
+	var got []bool
+	for range 7 {
+		got = append(got, policy(&Err{}))
+	}
+
+	assert.Equal(t, []bool{true, false, false, true, false, false, true}, got)
+}
+
+func TestStackSampled_LowerThanTwo(t *testing.T) {
+	for _, n := range []int{-1, 0, 1} {
+		policy := StackSampled(n)
+		assert.True(t, policy(&Err{}))
+		assert.True(t, policy(&Err{}))
+	}
+}
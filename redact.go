@@ -0,0 +1,354 @@
+package xerr
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Output is the destination of a rendered error, for which a [Redactor]
+// redacts the sensitive data of the error.
+type Output int
+
+// Outputs of a rendered error.
+const (
+	// OutputLog is the output of [Err.Error], [Err.Format], [Err.LogValue],
+	// [Err.MarshalJSON] and [Err.JSON], meant for logs and internal tools.
+	OutputLog Output = iota + 1
+
+	// OutputClient is the output of the responses sent to clients, e.g. the
+	// httpx package, or [Err.JSONFor] with OutputClient.
+	OutputClient
+)
+
+// String returns the name of the output, "log" or "client".
+func (o Output) String() string {
+	switch o {
+	case OutputLog:
+		return "log"
+	case OutputClient:
+		return "client"
+	default:
+		return "unknown"
+	}
+}
+
+// RedactedValue replaces the string fields redacted by [TagRedactor].
+const RedactedValue = "[REDACTED]"
+
+// Redactor redacts the sensitive data of the Msg and Details of an error
+// before it is rendered for an output. The value of each field of its Tags is
+// also redacted, passed as details with an empty msg. Redact must not modify
+// details: it returns a redacted copy instead.
+type Redactor interface {
+	Redact(msg string, details any, out Output) (string, any)
+}
+
+// RedactorFunc is an adapter to use an ordinary function as a [Redactor].
+type RedactorFunc func(msg string, details any, out Output) (string, any)
+
+// Redact calls f(msg, details, out).
+func (f RedactorFunc) Redact(msg string, details any, out Output) (string, any) {
+	return f(msg, details, out)
+}
+
+// TagRedactor is the default [Redactor]. It keeps Msg and redacts the fields
+// of the structs of Details according to their xerr struct tag:
+//
+//	Token string `json:"token" xerr:"secret"` // redacted for every output
+//	Email string `json:"email" xerr:"redact"` // redacted for OutputClient
+//
+// A redacted string field is replaced by [RedactedValue], and any other
+// redacted field by its zero value, so that Details keeps its type. Tags are
+// also honored in nested structs, pointers, slices, arrays and maps.
+type TagRedactor struct{}
+
+// Redact implements [Redactor].
+func (TagRedactor) Redact(msg string, details any, out Output) (string, any) {
+	if details == nil || !hasRedactTags(reflect.TypeOf(details)) {
+		return msg, details
+	}
+
+	redacted, changed := redactValue(reflect.ValueOf(details), out, 0)
+	if !changed {
+		return msg, details
+	}
+	return msg, redacted.Interface()
+}
+
+// redactor holds the [Redactor] set with [SetRedactor].
+type redactor struct {
+	Redactor
+}
+
+// currentRedactor is the [Redactor] used when rendering errors.
+var currentRedactor atomic.Pointer[redactor]
+
+func init() {
+	currentRedactor.Store(&redactor{TagRedactor{}})
+}
+
+// SetRedactor sets the [Redactor] applied to the Msg, Details and Tags of each
+// error of the chain when it is rendered, and returns the previous one. A nil
+// r restores the default [TagRedactor].
+//
+// Example:
+//
+//	xerr.SetRedactor(xerr.RedactorFunc(func(msg string, details any, out xerr.Output) (string, any) {
+//		if out == xerr.OutputClient {
+//			return emailPattern.ReplaceAllString(msg, xerr.RedactedValue), nil
+//		}
+//		return xerr.TagRedactor{}.Redact(msg, details, out)
+//	}))
+func SetRedactor(r Redactor) Redactor {
+	if r == nil {
+		r = TagRedactor{}
+	}
+	return currentRedactor.Swap(&redactor{r}).Redactor
+}
+
+// redact returns the Msg and Details of e redacted for out by the [Redactor]
+// set with [SetRedactor].
+func (e *Err) redact(out Output) (string, any) {
+	return currentRedactor.Load().Redact(e.Msg, e.Details, out)
+}
+
+// redactTags returns a copy of the Tags of e whose values are redacted for out
+// by the [Redactor] set with [SetRedactor], or nil if e has no tags.
+func (e *Err) redactTags(out Output) Fields {
+	if len(e.Tags) == 0 {
+		return nil
+	}
+
+	r := currentRedactor.Load()
+	tags := make(Fields, len(e.Tags))
+	for i, field := range e.Tags {
+		_, value := r.Redact("", field.Value, out)
+		tags[i] = Field{Key: field.Key, Value: value}
+	}
+	return tags
+}
+
+// Redacted returns a deep copy of the receiver whose Msg, Details and Tags of
+// each error of the tree are redacted for out by the [Redactor] set with
+// [SetRedactor]. Returns nil if called on a nil pointer.
+func (e *Err) Redacted(out Output) *Err {
+	clone := e.Clone()
+	clone.Walk(func(link *Err) bool {
+		link.Msg, link.Details = link.redact(out)
+		link.Tags = link.redactTags(out)
+		return true
+	})
+	return clone
+}
+
+// maxRedactDepth bounds the recursion of [redactValue] for self-referencing
+// values. The values beyond it that may contain tagged fields are dropped.
+const maxRedactDepth = 32
+
+// redactTag returns the xerr tag of field, e.g. "secret" or "redact".
+func redactTag(field reflect.StructField) string {
+	tag, _, _ := strings.Cut(field.Tag.Get("xerr"), ",")
+	return tag
+}
+
+// redacts reports whether a field with the xerr tag is redacted for out.
+func redacts(tag string, out Output) bool {
+	switch tag {
+	case "secret":
+		return true
+	case "redact":
+		return out == OutputClient
+	default:
+		return false
+	}
+}
+
+// redactTypes caches whether a type may contain fields with an xerr tag.
+var redactTypes sync.Map // map[reflect.Type]bool
+
+// hasRedactTags reports whether values of type t may contain fields with an
+// xerr tag.
+func hasRedactTags(t reflect.Type) bool {
+	if has, ok := redactTypes.Load(t); ok {
+		return has.(bool)
+	}
+
+	has := typeHasRedactTags(t, map[reflect.Type]bool{})
+	redactTypes.Store(t, has)
+	return has
+}
+
+// typeHasRedactTags implements [hasRedactTags], seen holding the types
+// already being checked.
+func typeHasRedactTags(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		return typeHasRedactTags(t.Elem(), seen)
+	case reflect.Map:
+		return typeHasRedactTags(t.Elem(), seen)
+	case reflect.Struct:
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if redactTag(field) != "" || typeHasRedactTags(field.Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redactValue returns a copy of v with the fields tagged for out redacted,
+// and whether anything was redacted. v is returned as is if not. Beyond
+// [maxRedactDepth], v is replaced by its zero value if it may contain tagged
+// fields.
+func redactValue(v reflect.Value, out Output, depth int) (reflect.Value, bool) {
+	if !v.IsValid() {
+		return v, false
+	}
+	if depth > maxRedactDepth {
+		if !hasRedactTags(v.Type()) {
+			return v, false
+		}
+		return reflect.Zero(v.Type()), true
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return v, false
+		}
+		elem, changed := redactValue(v.Elem(), out, depth+1)
+		if !changed {
+			return v, false
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(elem)
+		return copied, true
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v, false
+		}
+		elem, changed := redactValue(v.Elem(), out, depth+1)
+		if !changed {
+			return v, false
+		}
+		copied := reflect.New(v.Type().Elem())
+		copied.Elem().Set(elem)
+		return copied, true
+
+	case reflect.Struct:
+		return redactStruct(v, out, depth)
+
+	case reflect.Slice, reflect.Array:
+		return redactElems(v, out, depth)
+
+	case reflect.Map:
+		return redactMap(v, out, depth)
+	}
+
+	return v, false
+}
+
+// redactStruct implements [redactValue] for a struct.
+func redactStruct(v reflect.Value, out Output, depth int) (reflect.Value, bool) {
+	var copied reflect.Value
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		value := v.Field(i)
+		if redacts(redactTag(field), out) {
+			value = redactedField(field.Type)
+		} else if redactedValue, changed := redactValue(value, out, depth+1); changed {
+			value = redactedValue
+		} else {
+			continue
+		}
+
+		if !copied.IsValid() {
+			copied = reflect.New(v.Type()).Elem()
+			copied.Set(v)
+		}
+		copied.Field(i).Set(value)
+	}
+
+	if !copied.IsValid() {
+		return v, false
+	}
+	return copied, true
+}
+
+// redactElems implements [redactValue] for a slice or an array.
+func redactElems(v reflect.Value, out Output, depth int) (reflect.Value, bool) {
+	if v.Kind() == reflect.Slice && v.IsNil() {
+		return v, false
+	}
+
+	var copied reflect.Value
+	for i := range v.Len() {
+		elem, changed := redactValue(v.Index(i), out, depth+1)
+		if !changed {
+			continue
+		}
+
+		if !copied.IsValid() {
+			if v.Kind() == reflect.Slice {
+				copied = reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+				reflect.Copy(copied, v)
+			} else {
+				copied = reflect.New(v.Type()).Elem()
+				copied.Set(v)
+			}
+		}
+		copied.Index(i).Set(elem)
+	}
+
+	if !copied.IsValid() {
+		return v, false
+	}
+	return copied, true
+}
+
+// redactMap implements [redactValue] for a map.
+func redactMap(v reflect.Value, out Output, depth int) (reflect.Value, bool) {
+	if v.IsNil() {
+		return v, false
+	}
+
+	changed := false
+	copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+	for iter := v.MapRange(); iter.Next(); {
+		elem, elemChanged := redactValue(iter.Value(), out, depth+1)
+		changed = changed || elemChanged
+		copied.SetMapIndex(iter.Key(), elem)
+	}
+
+	if !changed {
+		return v, false
+	}
+	return copied, true
+}
+
+// redactedField returns the value of a redacted field of type t:
+// [RedactedValue] for a string, its zero value otherwise.
+func redactedField(t reflect.Type) reflect.Value {
+	if t.Kind() == reflect.String {
+		return reflect.ValueOf(RedactedValue).Convert(t)
+	}
+	return reflect.Zero(t)
+}
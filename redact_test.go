@@ -0,0 +1,311 @@
+package xerr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// credentials are details with fields redacted for every output and for
+// clients only.
+type credentials struct {
+	User   string `json:"user"`
+	Email  string `json:"email" xerr:"redact"`
+	Token  string `json:"token" xerr:"secret"`
+	PIN    int    `json:"pin" xerr:"secret"`
+	hidden string
+}
+
+// account nests credentials in every supported kind of value.
+type account struct {
+	ID       int                    `json:"id"`
+	Owner    credentials            `json:"owner"`
+	Backup   *credentials           `json:"backup"`
+	Previous []credentials          `json:"previous"`
+	ByName   map[string]credentials `json:"by_name"`
+	Extra    any                    `json:"extra"`
+}
+
+// newTestCredentials returns credentials with every field set.
+func newTestCredentials() credentials {
+	return credentials{User: "bob", Email: "bob@example.com", Token: "t0k3n", PIN: 1234, hidden: "h"}
+}
+
+// setRedactor sets the redactor for the duration of the test.
+func setRedactor(t *testing.T, r Redactor) {
+	t.Helper()
+
+	prev := SetRedactor(r)
+	t.Cleanup(func() { SetRedactor(prev) })
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of TagRedactor
+//
+// ----------------------------------------------------------------------------
+
+func TestOutput_String(t *testing.T) {
+	assert.Equal(t, "log", OutputLog.String())
+	assert.Equal(t, "client", OutputClient.String())
+	assert.Equal(t, "unknown", Output(0).String())
+}
+
+func TestTagRedactor_Redact(t *testing.T) {
+	tests := []struct {
+		name string
+		out  Output
+		want credentials
+	}{
+		{
+			name: "log",
+			out:  OutputLog,
+			want: credentials{User: "bob", Email: "bob@example.com", Token: RedactedValue, hidden: "h"},
+		},
+		{
+			name: "client",
+			out:  OutputClient,
+			want: credentials{User: "bob", Email: RedactedValue, Token: RedactedValue, hidden: "h"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details := newTestCredentials()
+
+			msg, redacted := TagRedactor{}.Redact("login failed", details, tt.out)
+
+			assert.Equal(t, "login failed", msg)
+			assert.Equal(t, tt.want, redacted)
+			assert.Equal(t, newTestCredentials(), details)
+		})
+	}
+}
+
+func TestTagRedactor_Redact_Nested(t *testing.T) {
+	backup := newTestCredentials()
+	details := &account{
+		ID:       42,
+		Owner:    newTestCredentials(),
+		Backup:   &backup,
+		Previous: []credentials{newTestCredentials()},
+		ByName:   map[string]credentials{"bob": newTestCredentials()},
+		Extra:    newTestCredentials(),
+	}
+
+	_, redacted := TagRedactor{}.Redact("", details, OutputClient)
+
+	got, ok := redacted.(*account)
+	require.True(t, ok)
+	assert.Equal(t, 42, got.ID)
+	assert.Equal(t, RedactedValue, got.Owner.Token)
+	assert.Equal(t, RedactedValue, got.Backup.Email)
+	assert.Equal(t, RedactedValue, got.Previous[0].Token)
+	assert.Equal(t, RedactedValue, got.ByName["bob"].Email)
+	assert.Equal(t, RedactedValue, got.Extra.(credentials).Token)
+
+	assert.Equal(t, "t0k3n", details.Owner.Token, "details must not be modified")
+	assert.Equal(t, "bob@example.com", details.Backup.Email, "details must not be modified")
+	assert.Equal(t, "t0k3n", details.Previous[0].Token, "details must not be modified")
+}
+
+func TestTagRedactor_Redact_Unchanged(t *testing.T) {
+	type plain struct {
+		Name string
+	}
+
+	tests := []struct {
+		name    string
+		details any
+	}{
+		{name: "nil", details: nil},
+		{name: "string", details: "details"},
+		{name: "untagged struct", details: plain{Name: "bob"}},
+		{name: "map of any", details: map[string]any{"user_id": 42}},
+		{name: "nil pointer", details: (*credentials)(nil)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, redacted := TagRedactor{}.Redact("", tt.details, OutputClient)
+			assert.Equal(t, tt.details, redacted)
+		})
+	}
+}
+
+func TestTagRedactor_Redact_SelfReferencing(t *testing.T) {
+	type node struct {
+		Secret string `xerr:"secret"`
+		Next   *node
+	}
+	n := &node{Secret: "s"}
+	n.Next = n
+
+	_, redacted := TagRedactor{}.Redact("", n, OutputLog)
+
+	assert.Equal(t, RedactedValue, redacted.(*node).Secret)
+	assert.Equal(t, "s", n.Secret)
+}
+
+func TestTagRedactor_Redact_TooDeep(t *testing.T) {
+	type node struct {
+		Secret string `xerr:"secret"`
+		Name   string
+		Next   *node
+	}
+	var n *node
+	for range 2 * maxRedactDepth {
+		n = &node{Secret: "s3cr3t", Name: "name", Next: n}
+	}
+
+	_, redacted := TagRedactor{}.Redact("", n, OutputLog)
+
+	data, err := json.Marshal(redacted)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "s3cr3t")
+	assert.Equal(t, "name", redacted.(*node).Next.Name)
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of SetRedactor()
+//
+// ----------------------------------------------------------------------------
+
+func TestSetRedactor(t *testing.T) {
+	setRedactor(t, RedactorFunc(func(msg string, details any, out Output) (string, any) {
+		return strings.ReplaceAll(msg, "bob", RedactedValue), nil
+	}))
+
+	e := New(errFetchFailed, "cannot fetch bob", newTestCredentials(), 500, nil)
+
+	assert.Contains(t, e.Error(), "msg=cannot fetch [REDACTED]")
+	assert.NotContains(t, e.Error(), "t0k3n")
+}
+
+func TestSetRedactor_Nil(t *testing.T) {
+	setRedactor(t, RedactorFunc(func(msg string, details any, out Output) (string, any) {
+		return "", nil
+	}))
+
+	prev := SetRedactor(nil)
+
+	assert.IsType(t, RedactorFunc(nil), prev)
+	_, redacted := Make(errFetchFailed, WithMsg(""), WithDetails(newTestCredentials())).redact(OutputLog)
+	assert.Equal(t, RedactedValue, redacted.(credentials).Token)
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of the redaction of the outputs
+//
+// ----------------------------------------------------------------------------
+
+func TestErr_Redacted(t *testing.T) {
+	e := Make(errFetchFailed,
+		WithMsg("cannot log in"),
+		WithDetails(newTestCredentials()),
+		WithPrev(Make(errDNS, WithMsg("dns error"), WithDetails(newTestCredentials()))),
+		WithCauses(Make(errTimeout, WithMsg("profile service"), WithDetails(newTestCredentials()))),
+	)
+
+	redacted := e.Redacted(OutputClient)
+
+	assert.Equal(t, RedactedValue, redacted.Details.(credentials).Email)
+	assert.Equal(t, RedactedValue, redacted.Prev.Details.(credentials).Email)
+	assert.Equal(t, RedactedValue, redacted.Causes[0].Details.(credentials).Email)
+	assert.Equal(t, "bob@example.com", e.Details.(credentials).Email, "receiver must not be modified")
+}
+
+func TestErr_Redacted_Nil(t *testing.T) {
+	var e *Err
+	assert.Nil(t, e.Redacted(OutputClient))
+}
+
+func TestErr_Redact_Outputs(t *testing.T) {
+	e := New(errFetchFailed, "cannot log in", newTestCredentials(), 500,
+		Make(errDNS, WithMsg("dns error"), WithDetails(newTestCredentials())))
+
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	record, err := json.Marshal(logJSON(t, "error", e)["error"])
+	require.NoError(t, err)
+
+	outputs := map[string]string{
+		"Error": e.Error(),
+		"%+v":   fmt.Sprintf("%+v", e),
+		"%#v":   fmt.Sprintf("%#v", e),
+		"JSON":  string(data),
+		"slog":  string(record),
+	}
+	for name, out := range outputs {
+		assert.NotContains(t, out, "t0k3n", name)
+		assert.Contains(t, out, "bob@example.com", name)
+	}
+}
+
+func TestErr_JSONFor(t *testing.T) {
+	e := New(errFetchFailed, "cannot log in", newTestCredentials(), 500, nil)
+
+	tests := []struct {
+		name      string
+		out       Output
+		wantEmail string
+	}{
+		{name: "log", out: OutputLog, wantEmail: "bob@example.com"},
+		{name: "client", out: OutputClient, wantEmail: RedactedValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := e.JSONFor(tt.out)
+			require.NoError(t, err)
+
+			var got struct {
+				Details credentials `json:"details"`
+			}
+			require.NoError(t, json.Unmarshal(data, &got))
+			assert.Equal(t, tt.wantEmail, got.Details.Email)
+			assert.Equal(t, RedactedValue, got.Details.Token)
+			assert.NotContains(t, string(data), "stack_trace")
+		})
+	}
+}
+
+func TestErr_Redact_Tags(t *testing.T) {
+	e := Make(errFetchFailed, WithPrev(NewSimple(errDNS, "dns error", nil).With("login", newTestCredentials())))
+	e = e.With("user_id", 42).With("login", newTestCredentials())
+
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	record, err := json.Marshal(logJSON(t, "error", e)["error"])
+	require.NoError(t, err)
+
+	outputs := map[string]string{
+		"Error": e.Error(),
+		"%+v":   fmt.Sprintf("%+v", e),
+		"%#v":   fmt.Sprintf("%#v", e),
+		"JSON":  string(data),
+		"slog":  string(record),
+	}
+	for name, out := range outputs {
+		assert.NotContains(t, out, "t0k3n", name)
+		assert.Contains(t, out, "bob@example.com", name)
+	}
+
+	data, err = e.JSONFor(OutputClient)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "t0k3n")
+	assert.NotContains(t, string(data), "bob@example.com")
+	assert.Contains(t, string(data), `"user_id":42`)
+
+	redacted := e.Redacted(OutputClient)
+	login, _ := redacted.Prev.Tags.Get("login")
+	assert.Equal(t, RedactedValue, login.(credentials).Email)
+	login, _ = e.Tags.Get("login")
+	assert.Equal(t, "bob@example.com", login.(credentials).Email, "receiver must not be modified")
+}
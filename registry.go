@@ -0,0 +1,192 @@
+package xerr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	// ErrAlreadyRegistered is returned when registering a sentinel or an ID
+	// that is already registered.
+	ErrAlreadyRegistered = errors.New("xerr: already registered")
+
+	// ErrInvalidRegistration is returned when registering a nil or
+	// non-comparable sentinel, or an empty ID.
+	ErrInvalidRegistration = errors.New("xerr: invalid registration")
+)
+
+// sentinels is the registry of sentinel errors, indexed both by ID and by
+// sentinel.
+var sentinels = struct {
+	sync.RWMutex
+	byID       map[string]error
+	bySentinel map[error]string
+}{
+	byID:       make(map[string]error),
+	bySentinel: make(map[error]string),
+}
+
+// Register registers sentinel under the stable id. When an Err whose Value is
+// sentinel is encoded with [Err.MarshalJSON], id is emitted as "value_id", and
+// [Err.UnmarshalJSON] maps it back to the same sentinel, so [Err.Is] keeps
+// working after a JSON round trip.
+//
+// It returns an error wrapping [ErrAlreadyRegistered] if either sentinel or
+// id is already registered, and [ErrInvalidRegistration] if sentinel is nil or
+// not comparable, or if id is empty. Register is safe for concurrent use.
+//
+// Example:
+//
+//	var ErrNotFound = errors.New("not found")
+//
+//	func init() {
+//		xerr.MustRegister(ErrNotFound, "not_found")
+//	}
+func Register(sentinel error, id string) error {
+	if sentinel == nil || id == "" || !reflect.TypeOf(sentinel).Comparable() {
+		return fmt.Errorf("%w: sentinel %v with id %q", ErrInvalidRegistration, sentinel, id)
+	}
+
+	sentinels.Lock()
+	defer sentinels.Unlock()
+
+	if _, ok := sentinels.byID[id]; ok {
+		return fmt.Errorf("%w: id %q", ErrAlreadyRegistered, id)
+	}
+	if other, ok := sentinels.bySentinel[sentinel]; ok {
+		return fmt.Errorf("%w: sentinel %q with id %q", ErrAlreadyRegistered, sentinel, other)
+	}
+
+	sentinels.byID[id] = sentinel
+	sentinels.bySentinel[sentinel] = id
+
+	return nil
+}
+
+// MustRegister is like [Register] but panics if the registration fails. It
+// is intended to be called from package init functions.
+func MustRegister(sentinel error, id string) {
+	if err := Register(sentinel, id); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the sentinel registered under id.
+func Lookup(id string) (error, bool) {
+	sentinels.RLock()
+	defer sentinels.RUnlock()
+
+	sentinel, ok := sentinels.byID[id]
+	return sentinel, ok
+}
+
+// SentinelID returns the ID sentinel is registered under. Only the registered
+// error itself matches, not errors wrapping it.
+func SentinelID(sentinel error) (string, bool) {
+	if sentinel == nil || !reflect.TypeOf(sentinel).Comparable() {
+		return "", false
+	}
+
+	sentinels.RLock()
+	defer sentinels.RUnlock()
+
+	id, ok := sentinels.bySentinel[sentinel]
+	return id, ok
+}
+
+// detailTypes is the registry of details types, indexed both by name and by
+// type.
+var detailTypes = struct {
+	sync.RWMutex
+	byName map[string]reflect.Type
+	byType map[reflect.Type]string
+}{
+	byName: make(map[string]reflect.Type),
+	byType: make(map[reflect.Type]string),
+}
+
+// RegisterDetails registers the details type T under name. When an Err whose
+// Details is a T is encoded with [Err.MarshalJSON], name is emitted as
+// "details_type", and [Err.UnmarshalJSON] decodes the details back into a T
+// instead of a generic map[string]any.
+//
+// It returns an error wrapping [ErrAlreadyRegistered] if either T or name is
+// already registered, and [ErrInvalidRegistration] if T is an interface type
+// or if name is empty. RegisterDetails is safe for concurrent use.
+//
+// Example:
+//
+//	type ValidationDetails struct {
+//		Field string `json:"field"`
+//	}
+//
+//	func init() {
+//		xerr.MustRegisterDetails[ValidationDetails]("validation")
+//	}
+func RegisterDetails[T any](name string) error {
+	typ := reflect.TypeFor[T]()
+	if name == "" || typ.Kind() == reflect.Interface {
+		return fmt.Errorf("%w: details type %s with name %q", ErrInvalidRegistration, typ, name)
+	}
+
+	detailTypes.Lock()
+	defer detailTypes.Unlock()
+
+	if _, ok := detailTypes.byName[name]; ok {
+		return fmt.Errorf("%w: details name %q", ErrAlreadyRegistered, name)
+	}
+	if other, ok := detailTypes.byType[typ]; ok {
+		return fmt.Errorf("%w: details type %s with name %q", ErrAlreadyRegistered, typ, other)
+	}
+
+	detailTypes.byName[name] = typ
+	detailTypes.byType[typ] = name
+
+	return nil
+}
+
+// MustRegisterDetails is like [RegisterDetails] but panics if the
+// registration fails. It is intended to be called from package init
+// functions.
+func MustRegisterDetails[T any](name string) {
+	if err := RegisterDetails[T](name); err != nil {
+		panic(err)
+	}
+}
+
+// detailsName returns the name the type of details is registered under.
+func detailsName(details any) (string, bool) {
+	if details == nil {
+		return "", false
+	}
+
+	detailTypes.RLock()
+	defer detailTypes.RUnlock()
+
+	name, ok := detailTypes.byType[reflect.TypeOf(details)]
+	return name, ok
+}
+
+// decodeDetails decodes the JSON details data into the type registered under
+// name, or with the default rules of [json.Unmarshal] if name is not
+// registered.
+func decodeDetails(data []byte, name string) (any, error) {
+	detailTypes.RLock()
+	typ, ok := detailTypes.byName[name]
+	detailTypes.RUnlock()
+
+	if !ok {
+		var details any
+		err := json.Unmarshal(data, &details)
+		return details, err
+	}
+
+	details := reflect.New(typ)
+	if err := json.Unmarshal(data, details.Interface()); err != nil {
+		return nil, err
+	}
+	return details.Elem().Interface(), nil
+}
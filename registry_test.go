@@ -0,0 +1,153 @@
+package xerr
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// register registers sentinel under id for the duration of the test.
+func register(t *testing.T, sentinel error, id string) {
+	t.Helper()
+
+	assert.NoError(t, Register(sentinel, id))
+	t.Cleanup(func() {
+		sentinels.Lock()
+		defer sentinels.Unlock()
+
+		delete(sentinels.byID, id)
+		delete(sentinels.bySentinel, sentinel)
+	})
+}
+
+// uncomparableError is an error type that cannot be used as a map key.
+type uncomparableError []string
+
+func (u uncomparableError) Error() string {
+	return "uncomparable"
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Register()
+//
+// ----------------------------------------------------------------------------
+
+func TestRegister(t *testing.T) {
+	sentinel := errors.New("not found")
+	register(t, sentinel, "test.not_found")
+
+	got, ok := Lookup("test.not_found")
+	assert.True(t, ok)
+	assert.Same(t, sentinel, got)
+
+	id, ok := SentinelID(sentinel)
+	assert.True(t, ok)
+	assert.Equal(t, "test.not_found", id)
+}
+
+func TestRegister_DuplicateID(t *testing.T) {
+	register(t, errors.New("not found"), "test.not_found")
+
+	err := Register(errors.New("other"), "test.not_found")
+	assert.ErrorIs(t, err, ErrAlreadyRegistered)
+}
+
+func TestRegister_DuplicateSentinel(t *testing.T) {
+	sentinel := errors.New("not found")
+	register(t, sentinel, "test.not_found")
+
+	err := Register(sentinel, "test.other")
+	assert.ErrorIs(t, err, ErrAlreadyRegistered)
+
+	_, ok := Lookup("test.other")
+	assert.False(t, ok)
+}
+
+func TestRegister_Invalid(t *testing.T) {
+	assert.ErrorIs(t, Register(nil, "test.nil"), ErrInvalidRegistration)
+	assert.ErrorIs(t, Register(errors.New("empty id"), ""), ErrInvalidRegistration)
+	assert.ErrorIs(t, Register(uncomparableError{"a"}, "test.uncomparable"), ErrInvalidRegistration)
+}
+
+func TestMustRegister_Panics(t *testing.T) {
+	register(t, errors.New("not found"), "test.not_found")
+
+	assert.Panics(t, func() { MustRegister(errors.New("other"), "test.not_found") })
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Lookup() and SentinelID()
+//
+// ----------------------------------------------------------------------------
+
+func TestLookup_Unknown(t *testing.T) {
+	got, ok := Lookup("test.unknown")
+	assert.False(t, ok)
+	assert.Nil(t, got)
+}
+
+func TestSentinelID_Unknown(t *testing.T) {
+	sentinel := errors.New("not found")
+	register(t, sentinel, "test.not_found")
+
+	for _, err := range []error{nil, errors.New("not found"), uncomparableError{"a"}} {
+		id, ok := SentinelID(err)
+		assert.False(t, ok)
+		assert.Equal(t, "", id)
+	}
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of JSON encoding of registered sentinels
+//
+// ----------------------------------------------------------------------------
+
+func TestRegister_MarshalJSON(t *testing.T) {
+	sentinel := errors.New("not found")
+	register(t, sentinel, "test.not_found")
+
+	result, err := New(sentinel, "", nil, 0, nil).JSON()
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(result), `"value":"not found","value_id":"test.not_found"`)
+}
+
+func TestRegister_MarshalJSON_Unregistered(t *testing.T) {
+	result, err := New(errors.New("not found"), "", nil, 0, nil).JSON()
+
+	assert.NoError(t, err)
+	assert.NotContains(t, string(result), `"value_id"`)
+}
+
+func TestRegister_UnmarshalJSON(t *testing.T) {
+	sentinel := errors.New("not found")
+	other := errors.New("other")
+	register(t, sentinel, "test.not_found")
+
+	root := New(sentinel, "root", nil, 0, nil)
+	e := New(other, "outer", nil, 0, root)
+	data, err := e.JSON()
+	assert.NoError(t, err)
+
+	var decoded Err
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+
+	assert.Same(t, sentinel, decoded.Prev.Value)
+	assert.True(t, decoded.Is(sentinel))
+	assert.True(t, errors.Is(&decoded, sentinel))
+	assert.True(t, decoded.Prev.ValueEq(root))
+	assert.False(t, decoded.Is(other))
+}
+
+func TestRegister_UnmarshalJSON_UnknownID(t *testing.T) {
+	var decoded Err
+	err := json.Unmarshal([]byte(`{"value":"not found","value_id":"test.unknown"}`), &decoded)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "not found", decoded.Value.Error())
+}
@@ -0,0 +1,132 @@
+package xerr
+
+import (
+	"errors"
+	"time"
+)
+
+// Retryable reports whether the operation that failed with err is worth
+// retrying. err is an *Err, a *Frozen, or an error wrapping one of them.
+//
+// The classification is the Retryable field of the outermost error of the
+// Prev chain that sets it, so that a wrapping error overrides the one of its
+// causes. If no error of the chain sets it, a temporary error is retryable,
+// see [Temporary].
+//
+// Example:
+//
+//	err := Make(ErrUnavailable, WithRetryable(true), WithRetryAfter(time.Second))
+//	if xerr.Retryable(err) {
+//		time.Sleep(xerr.RetryAfter(err))
+//	}
+func Retryable(err error) bool {
+	if e := errOf(err); e != nil {
+		return e.retryable()
+	}
+
+	temporary, _ := temporaryValue(err)
+	return temporary
+}
+
+// Temporary reports whether err is caused by a transient condition, e.g. a
+// timeout or an unavailable service. err is an *Err, a *Frozen, or an error
+// wrapping one of them.
+//
+// The classification is the one of the outermost error of the Prev chain
+// that either sets its Temporary field, or whose Value implements
+// Temporary() bool, or Timeout() bool returning true, like the errors of the
+// net and context packages. A plain error is classified by its own methods.
+func Temporary(err error) bool {
+	if e := errOf(err); e != nil {
+		return e.temporary()
+	}
+
+	temporary, _ := temporaryValue(err)
+	return temporary
+}
+
+// RetryAfter returns the minimum delay before retrying the operation that
+// failed with err: the RetryAfter of the outermost error of the Prev chain
+// that sets it, or 0 if there is none.
+func RetryAfter(err error) time.Duration {
+	return errOf(err).retryAfter()
+}
+
+// errOf returns the *Err of err: err itself, the *Err of a *Frozen, or the
+// first *Err wrapped by err. Returns nil if there is none.
+func errOf(err error) *Err {
+	switch e := err.(type) {
+	case *Err:
+		return e
+	case *Frozen:
+		return e.err()
+	}
+
+	var e *Err
+	if errors.As(err, &e) {
+		return e
+	}
+	var f *Frozen
+	if errors.As(err, &f) {
+		return f.err()
+	}
+	return nil
+}
+
+// retryable implements [Retryable] for the Prev chain of e.
+func (e *Err) retryable() bool {
+	for link := range e.All() {
+		if link.Retryable != nil {
+			return *link.Retryable
+		}
+	}
+	return e.temporary()
+}
+
+// temporary implements [Temporary] for the Prev chain of e.
+func (e *Err) temporary() bool {
+	for link := range e.All() {
+		if link.Temporary != nil {
+			return *link.Temporary
+		}
+		if temporary, ok := temporaryValue(link.Value); ok {
+			return temporary
+		}
+	}
+	return false
+}
+
+// retryAfter implements [RetryAfter] for the Prev chain of e.
+func (e *Err) retryAfter() time.Duration {
+	for link := range e.All() {
+		if link.RetryAfter > 0 {
+			return link.RetryAfter
+		}
+	}
+	return 0
+}
+
+// temporaryValue classifies err with its Temporary() bool method, or its
+// Timeout() bool method if it returns true, and reports whether err was
+// classified.
+func temporaryValue(err error) (temporary, ok bool) {
+	var t interface{ Temporary() bool }
+	if errors.As(err, &t) {
+		return t.Temporary(), true
+	}
+
+	var timeout interface{ Timeout() bool }
+	if errors.As(err, &timeout) && timeout.Timeout() {
+		return true, true
+	}
+	return false, false
+}
+
+// cloneFlag returns a copy of the optional flag b, or nil if b is nil.
+func cloneFlag(b *bool) *bool {
+	if b == nil {
+		return nil
+	}
+	flag := *b
+	return &flag
+}
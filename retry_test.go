@@ -0,0 +1,245 @@
+package xerr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// timeoutError is an error implementing Timeout() bool, like net.Error.
+type timeoutError struct {
+	timeout bool
+}
+
+func (e timeoutError) Error() string { return "i/o timeout" }
+func (e timeoutError) Timeout() bool { return e.timeout }
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Retryable()
+//
+// ----------------------------------------------------------------------------
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "unclassified", err: NewSimple(errFetchFailed, "", nil), want: false},
+		{name: "retryable", err: Make(errFetchFailed, WithRetryable(true)), want: true},
+		{name: "not retryable", err: Make(errFetchFailed, WithRetryable(false), WithTemporary(true)), want: false},
+		{
+			name: "inherited",
+			err:  Make(errFetchFailed, WithPrev(Make(errTimeout, WithRetryable(true)))),
+			want: true,
+		},
+		{
+			name: "overridden",
+			err:  Make(errFetchFailed, WithRetryable(false), WithPrev(Make(errTimeout, WithRetryable(true)))),
+			want: false,
+		},
+		{name: "temporary", err: Make(errFetchFailed, WithTemporary(true)), want: true},
+		{name: "timeout value", err: NewSimple(timeoutError{timeout: true}, "", nil), want: true},
+		{name: "plain timeout", err: context.DeadlineExceeded, want: true},
+		{name: "plain error", err: errFetchFailed, want: false},
+		{name: "frozen", err: Make(errFetchFailed, WithRetryable(true)).Freeze(), want: true},
+		{name: "wrapped", err: fmt.Errorf("call: %w", Make(errFetchFailed, WithRetryable(true))), want: true},
+		{
+			name: "wrapped frozen",
+			err:  fmt.Errorf("call: %w", Make(errFetchFailed, WithRetryable(true)).Freeze()),
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Retryable(tt.err))
+		})
+	}
+}
+
+func TestRetryable_Catalog(t *testing.T) {
+	c := newTestCatalog().MustDefine(CodeInfo{Code: 1003, Name: "UPSTREAM_FAILED", Category: CategoryUnavailable})
+
+	assert.True(t, Retryable(c.New(testCodeTimeout, errTimeout)))
+	assert.False(t, Retryable(c.New(testCodeUserNotFound, errFetchFailed)))
+	assert.False(t, Retryable(c.New(testCodeUserNotFound, errFetchFailed, WithPrev(c.New(testCodeTimeout, errTimeout)))))
+	assert.False(t, Retryable(c.New(testCodeUserNotFound, errFetchFailed, WithPrev(Make(errTimeout, WithTemporary(true))))))
+	assert.Equal(t, new(false), c.New(testCodeUserNotFound, errFetchFailed).Retryable)
+	assert.True(t, Retryable(c.New(testCodeUserNotFound, errFetchFailed, WithRetryable(true))))
+
+	assert.Nil(t, c.New(1003, errFetchFailed).Retryable)
+	assert.False(t, Retryable(c.New(1003, errFetchFailed)))
+	assert.True(t, Retryable(c.New(1003, errFetchFailed, WithPrev(c.New(testCodeTimeout, errTimeout)))))
+	assert.True(t, Retryable(c.New(1003, errFetchFailed, WithPrev(Make(errTimeout, WithTemporary(true))))))
+	assert.True(t, Retryable(c.New(1999, errFetchFailed, WithPrev(c.New(testCodeTimeout, errTimeout)))))
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Temporary()
+//
+// ----------------------------------------------------------------------------
+
+func TestTemporary(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "unclassified", err: NewSimple(errFetchFailed, "", nil), want: false},
+		{name: "temporary", err: Make(errFetchFailed, WithTemporary(true)), want: true},
+		{name: "retryable only", err: Make(errFetchFailed, WithRetryable(true)), want: false},
+		{name: "timeout value", err: NewSimple(timeoutError{timeout: true}, "", nil), want: true},
+		{name: "no timeout value", err: NewSimple(timeoutError{timeout: false}, "", nil), want: false},
+		{name: "net error", err: NewSimple(&net.DNSError{IsTemporary: true}, "", nil), want: true},
+		{name: "non temporary net error", err: NewSimple(&net.DNSError{IsNotFound: true}, "", nil), want: false},
+		{
+			name: "wrapped value",
+			err:  Make(errFetchFailed, WithPrev(NewSimple(fmt.Errorf("dial: %w", context.DeadlineExceeded), "", nil))),
+			want: true,
+		},
+		{
+			name: "overridden value",
+			err:  Make(errFetchFailed, WithTemporary(false), WithPrev(NewSimple(context.DeadlineExceeded, "", nil))),
+			want: false,
+		},
+		{name: "plain error", err: context.Canceled, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Temporary(tt.err))
+		})
+	}
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of RetryAfter()
+//
+// ----------------------------------------------------------------------------
+
+func TestRetryAfter(t *testing.T) {
+	root := Make(errTimeout, WithRetryAfter(time.Second))
+
+	assert.Equal(t, time.Second, RetryAfter(root))
+	assert.Equal(t, time.Second, RetryAfter(Make(errFetchFailed, WithPrev(root))))
+	assert.Equal(t, time.Minute, RetryAfter(Make(errFetchFailed, WithRetryAfter(time.Minute), WithPrev(root))))
+	assert.Zero(t, RetryAfter(NewSimple(errFetchFailed, "", nil)))
+	assert.Zero(t, RetryAfter(errFetchFailed))
+	assert.Zero(t, RetryAfter(nil))
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of the retry classification of the outputs
+//
+// ----------------------------------------------------------------------------
+
+func TestErr_Retry_Clone(t *testing.T) {
+	e := Make(errFetchFailed, WithRetryable(true), WithTemporary(false), WithRetryAfter(time.Second))
+	clone := e.Clone()
+
+	*e.Retryable = false
+
+	assert.True(t, *clone.Retryable)
+	assert.False(t, *clone.Temporary)
+	assert.Equal(t, time.Second, clone.RetryAfter)
+}
+
+func TestErr_Retry_Option_NotShared(t *testing.T) {
+	retryable := WithRetryable(true)
+	e1 := Make(errFetchFailed, retryable)
+	e2 := Make(errFetchFailed, retryable)
+
+	*e1.Retryable = false
+
+	assert.True(t, *e2.Retryable)
+}
+
+func TestErr_Retry_Outputs(t *testing.T) {
+	e := Make(errFetchFailed, WithRetryable(true), WithTemporary(false), WithRetryAfter(1500*time.Millisecond))
+
+	assert.Contains(t, e.Error(), ", retryable=true, temporary=false, retry_after=1.5s")
+	assert.Contains(t, fmt.Sprintf("%+v", e), "\n    retryable: true\n    temporary: false\n    retry after: 1.5s")
+	assert.Contains(t, fmt.Sprintf("%#v", e), "Retryable:new(true), Temporary:new(false), RetryAfter:1500000000}")
+
+	record := logJSON(t, "error", e)["error"].(map[string]any)
+	assert.Equal(t, true, record["retryable"])
+	assert.Equal(t, false, record["temporary"])
+	assert.Equal(t, float64(1500*time.Millisecond), record["retry_after"])
+}
+
+func TestErr_Retry_JSON(t *testing.T) {
+	e := Make(errFetchFailed,
+		WithRetryable(false),
+		WithPrev(Make(errTimeout, WithTemporary(true), WithRetryAfter(1500*time.Millisecond))),
+	)
+
+	data, err := json.Marshal(e)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"retryable":false`)
+	assert.Contains(t, string(data), `"temporary":true,"retry_after":"1.5s"`)
+
+	var decoded Err
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, new(false), decoded.Retryable)
+	assert.Nil(t, decoded.Temporary)
+	assert.Equal(t, new(true), decoded.Prev.Temporary)
+	assert.Equal(t, 1500*time.Millisecond, decoded.Prev.RetryAfter)
+	assert.False(t, Retryable(&decoded))
+	assert.True(t, Temporary(&decoded))
+}
+
+func TestErr_Retry_JSON_Unclassified(t *testing.T) {
+	data, err := json.Marshal(NewSimple(errFetchFailed, "", nil))
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(data), `"retryable"`)
+	assert.NotContains(t, string(data), `"temporary"`)
+	assert.NotContains(t, string(data), `"retry_after"`)
+}
+
+func TestErr_UnmarshalJSON_InvalidRetryAfter(t *testing.T) {
+	var e Err
+	assert.Error(t, json.Unmarshal([]byte(`{"value":"test","retry_after":"soon"}`), &e))
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of the retry classification of Frozen
+//
+// ----------------------------------------------------------------------------
+
+func TestFrozen_Retry(t *testing.T) {
+	f := newTestFrozen()
+	g := f.WithRetryable(true).WithTemporary(true).WithRetryAfter(time.Second)
+
+	assert.False(t, f.Retryable())
+	assert.False(t, f.Temporary())
+	assert.Zero(t, f.RetryAfter())
+	assert.True(t, g.Retryable())
+	assert.True(t, g.Temporary())
+	assert.Equal(t, time.Second, g.RetryAfter())
+	assert.True(t, g.Wrap(errors.New("handler failed")).Retryable())
+}
+
+func TestFrozen_Retry_Nil(t *testing.T) {
+	var f *Frozen
+
+	assert.False(t, f.Retryable())
+	assert.False(t, f.Temporary())
+	assert.Zero(t, f.RetryAfter())
+	assert.False(t, Retryable(f))
+}
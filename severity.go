@@ -0,0 +1,85 @@
+package xerr
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Severity is the severity level of an error.
+type Severity int
+
+// Severity levels, from the least to the most severe.
+const (
+	SeverityUnspecified Severity = iota
+	SeverityDebug
+	SeverityInfo
+	SeverityWarn
+	SeverityError
+	SeverityCritical
+)
+
+// severityNames are the names of the severity levels.
+var severityNames = [...]string{
+	SeverityUnspecified: "unspecified",
+	SeverityDebug:       "debug",
+	SeverityInfo:        "info",
+	SeverityWarn:        "warn",
+	SeverityError:       "error",
+	SeverityCritical:    "critical",
+}
+
+// LevelCritical is the [slog.Level] of the errors of [SeverityCritical].
+const LevelCritical = slog.LevelError + 4
+
+// severityLevels are the [slog.Level] of the severity levels.
+var severityLevels = [...]slog.Level{
+	SeverityUnspecified: slog.LevelError,
+	SeverityDebug:       slog.LevelDebug,
+	SeverityInfo:        slog.LevelInfo,
+	SeverityWarn:        slog.LevelWarn,
+	SeverityError:       slog.LevelError,
+	SeverityCritical:    LevelCritical,
+}
+
+// String returns the lower-case name of the severity level, e.g. "warn".
+func (s Severity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return "unspecified"
+	}
+	return severityNames[s]
+}
+
+// Level returns the [slog.Level] of the severity level, e.g.
+// [slog.LevelWarn] for [SeverityWarn]. An error of unspecified severity is
+// logged at [slog.LevelError], and a critical one at [LevelCritical].
+func (s Severity) Level() slog.Level {
+	if s < 0 || int(s) >= len(severityLevels) {
+		return slog.LevelError
+	}
+	return severityLevels[s]
+}
+
+// MarshalText implements [encoding.TextMarshaler], encoding the severity level
+// as its name.
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler], decoding the name of a
+// severity level, case-insensitively. An empty name is the unspecified
+// severity.
+func (s *Severity) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*s = SeverityUnspecified
+		return nil
+	}
+
+	for severity, name := range severityNames {
+		if strings.EqualFold(name, string(text)) {
+			*s = Severity(severity)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown severity %q", text)
+}
@@ -0,0 +1,129 @@
+package xerr
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Severity
+//
+// ----------------------------------------------------------------------------
+
+func TestSeverity_String(t *testing.T) {
+	assert.Equal(t, "unspecified", SeverityUnspecified.String())
+	assert.Equal(t, "debug", SeverityDebug.String())
+	assert.Equal(t, "info", SeverityInfo.String())
+	assert.Equal(t, "warn", SeverityWarn.String())
+	assert.Equal(t, "error", SeverityError.String())
+	assert.Equal(t, "critical", SeverityCritical.String())
+	assert.Equal(t, "unspecified", Severity(-1).String())
+	assert.Equal(t, "unspecified", Severity(100).String())
+}
+
+func TestSeverity_Level(t *testing.T) {
+	assert.Equal(t, slog.LevelError, SeverityUnspecified.Level())
+	assert.Equal(t, slog.LevelDebug, SeverityDebug.Level())
+	assert.Equal(t, slog.LevelInfo, SeverityInfo.Level())
+	assert.Equal(t, slog.LevelWarn, SeverityWarn.Level())
+	assert.Equal(t, slog.LevelError, SeverityError.Level())
+	assert.Equal(t, LevelCritical, SeverityCritical.Level())
+	assert.Equal(t, slog.LevelError, Severity(100).Level())
+	assert.Equal(t, "ERROR+4", LevelCritical.String())
+}
+
+func TestSeverity_MarshalText(t *testing.T) {
+	data, err := json.Marshal(map[string]Severity{"severity": SeverityWarn})
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"severity":"warn"}`, string(data))
+}
+
+func TestSeverity_UnmarshalText(t *testing.T) {
+	tests := []struct {
+		text    string
+		want    Severity
+		wantErr bool
+	}{
+		{text: "", want: SeverityUnspecified},
+		{text: "debug", want: SeverityDebug},
+		{text: "WARN", want: SeverityWarn},
+		{text: "Critical", want: SeverityCritical},
+		{text: "fatal", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			var s Severity
+			err := s.UnmarshalText([]byte(tt.text))
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, s)
+		})
+	}
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of MaxSeverity()
+//
+// ----------------------------------------------------------------------------
+
+func TestErr_MaxSeverity(t *testing.T) {
+	root := Make(errDNS, WithSeverity(SeverityCritical))
+	middle := Make(errTimeout, WithSeverity(SeverityWarn), WithPrev(root))
+	outer := Make(errFetchFailed, WithSeverity(SeverityInfo), WithPrev(middle))
+
+	assert.Equal(t, SeverityCritical, outer.MaxSeverity())
+	assert.Equal(t, SeverityWarn, Make(errFetchFailed, WithPrev(Make(errTimeout, WithSeverity(SeverityWarn)))).MaxSeverity())
+	assert.Equal(t, SeverityUnspecified, NewSimple(errFetchFailed, "", nil).MaxSeverity())
+	assert.Equal(t, SeverityUnspecified, (*Err)(nil).MaxSeverity())
+}
+
+func TestErr_MaxSeverity_Causes(t *testing.T) {
+	cause := Make(errDNS, WithSeverity(SeverityCritical))
+	e := Make(errFetchFailed, WithSeverity(SeverityWarn), WithCauses(NewSimple(errTimeout, "", nil), Make(errTimeout, WithPrev(cause))))
+
+	assert.Equal(t, SeverityCritical, e.MaxSeverity())
+	assert.Equal(t, SeverityCritical, e.Freeze().MaxSeverity())
+	assert.Equal(t, LevelCritical, Level(e))
+	assert.Equal(t, SeverityWarn, Make(errFetchFailed, WithCauses(NewSimple(errTimeout, "", nil), Make(errDNS, WithSeverity(SeverityWarn)))).MaxSeverity())
+}
+
+func TestErr_Severity_Outputs(t *testing.T) {
+	e := Make(errFetchFailed, WithCode(500), WithSeverity(SeverityCritical))
+
+	assert.Contains(t, e.Error(), "code=500, severity=critical")
+	assert.Contains(t, fmt.Sprintf("%+v", e), "\n    code: 500\n    severity: critical")
+	assert.Contains(t, fmt.Sprintf("%#v", e), "Code:500, Severity:5,")
+	assert.Equal(t, SeverityCritical, e.Clone().Severity)
+
+	record := logJSON(t, "error", e)["error"].(map[string]any)
+	assert.Equal(t, "critical", record["severity"])
+
+	data, err := json.Marshal(e)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), `"code":500,"severity":"critical"`)
+
+	var decoded Err
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, SeverityCritical, decoded.Severity)
+}
+
+func TestErr_Severity_Unspecified(t *testing.T) {
+	e := NewSimple(errFetchFailed, "", nil)
+
+	data, err := json.Marshal(e)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), `"severity"`)
+	assert.NotContains(t, e.Error(), "severity=")
+}
@@ -0,0 +1,185 @@
+package xerr
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// logStackTrace reports whether [Err.LogValue] includes the stack trace.
+var logStackTrace atomic.Bool
+
+// SetLogStackTrace sets whether the stack trace is included in the log value
+// returned by [Err.LogValue], and returns the previous setting. The stack
+// trace is excluded by default.
+func SetLogStackTrace(enabled bool) bool {
+	return logStackTrace.Swap(enabled)
+}
+
+// LogValue implements [slog.LogValuer]. It returns a group with the value,
+// code, msg, source and timestamp of the Err, the symbolic name of its code if
+// declared in the default catalog, its severity if specified, its public
+// message, details and tags (in a nested "tags" group) if not empty, its
+// retry classification if set, its stack trace if enabled with
+// [SetLogStackTrace], a nested "prev" group for the previous error of the
+// chain, and a nested "causes" group with a group per cause, keyed by its
+// index. Msg, Details and Tags are redacted for [OutputLog] by the [Redactor]
+// set with [SetRedactor].
+//
+// The errors of the chain beyond the depth set with [SetMaxDepth] are
+// omitted, their number being logged as "more" in the group of the last
+// logged error. The Prev or cause of an error leading back to one of its
+// ancestors is omitted, and "cycle" is logged as true.
+//
+// Example:
+//
+//	slog.Error("request failed", "error", err)
+func (e *Err) LogValue() slog.Value {
+	if e == nil {
+		return slog.AnyValue(nil)
+	}
+	return e.logValue(0, &path{})
+}
+
+// logValue implements [Err.LogValue] for the link at index depth of a chain.
+func (e *Err) logValue(depth int, p *path) slog.Value {
+	p.push(e)
+	defer p.truncate(p.n - 1)
+
+	value := ""
+	if e.Value != nil {
+		value = e.Value.Error()
+	}
+	msg, details := e.redact(OutputLog)
+
+	attrs := []slog.Attr{
+		slog.String("value", value),
+		slog.Int("code", e.Code),
+		slog.String("msg", msg),
+		slog.String("source", fmt.Sprintf("%s:%d", e.File, e.Line)),
+		slog.Time("timestamp", time.UnixMicro(e.Timestamp)),
+	}
+
+	if name := codeName(e.Code); name != "" {
+		attrs = append(attrs, slog.String("code_name", name))
+	}
+
+	if e.Severity != SeverityUnspecified {
+		attrs = append(attrs, slog.String("severity", e.Severity.String()))
+	}
+
+	if e.PublicMsg != "" {
+		attrs = append(attrs, slog.String("public_msg", e.PublicMsg))
+	}
+
+	if details != nil {
+		attrs = append(attrs, slog.Any("details", details))
+	}
+
+	if fields := e.redactTags(OutputLog); len(fields) > 0 {
+		tags := make([]slog.Attr, 0, len(fields))
+		for _, field := range fields {
+			tags = append(tags, slog.Any(field.Key, field.Value))
+		}
+		attrs = append(attrs, slog.Attr{Key: "tags", Value: slog.GroupValue(tags...)})
+	}
+
+	if e.Retryable != nil {
+		attrs = append(attrs, slog.Bool("retryable", *e.Retryable))
+	}
+
+	if e.Temporary != nil {
+		attrs = append(attrs, slog.Bool("temporary", *e.Temporary))
+	}
+
+	if e.RetryAfter > 0 {
+		attrs = append(attrs, slog.Duration("retry_after", e.RetryAfter))
+	}
+
+	if logStackTrace.Load() {
+		if frames := e.Frames(); len(frames) > 0 {
+			attrs = append(attrs, slog.Any("stack_trace", frames))
+		}
+	}
+
+	cycle := false
+	switch {
+	case e.Prev == nil:
+	case p.contains(e.Prev):
+		cycle = true
+	case truncated(depth + 1):
+		attrs = append(attrs, slog.Int("more", e.Prev.Depth()))
+	default:
+		attrs = append(attrs, slog.Attr{Key: "prev", Value: e.Prev.logValue(depth+1, p)})
+	}
+
+	if len(e.Causes) > 0 {
+		causes := make([]slog.Attr, 0, len(e.Causes))
+		for i, cause := range e.Causes {
+			switch {
+			case cause == nil:
+			case p.contains(cause):
+				cycle = true
+			default:
+				causes = append(causes, slog.Attr{Key: strconv.Itoa(i), Value: cause.logValue(0, p)})
+			}
+		}
+		attrs = append(attrs, slog.Attr{Key: "causes", Value: slog.GroupValue(causes...)})
+	}
+
+	if cycle {
+		attrs = append(attrs, slog.Bool("cycle", true))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// Level returns the [slog.Level] at which err should be logged: the level of
+// the highest severity of its tree (see [Err.MaxSeverity] and
+// [Severity.Level]) if err is an *Err, a *Frozen, or an error wrapping one of
+// them, and [slog.LevelError] otherwise.
+func Level(err error) slog.Level {
+	return errOf(err).MaxSeverity().Level()
+}
+
+// Log logs err with logger at the level returned by [Level], with the message
+// msg, the attributes args, and err as an "error" attribute (see [Attr]). The
+// default logger is used if logger is nil.
+//
+// Example:
+//
+//	xerr.Log(ctx, logger, "request failed", err, "path", r.URL.Path)
+func Log(ctx context.Context, logger *slog.Logger, msg string, err error, args ...any) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Log(ctx, Level(err), msg, append(args[:len(args):len(args)], Attr(err))...)
+}
+
+// Attr returns an [slog.Attr] with the key "error" for err. An *Err, a
+// *Frozen or an error wrapping one of them is logged as the group returned by
+// [Err.LogValue] for that error, any other error as its message. When err
+// wraps the *Err or *Frozen, its own message is kept as "error" in the group.
+//
+// Example:
+//
+//	slog.Error("request failed", xerr.Attr(err))
+func Attr(err error) slog.Attr {
+	switch e := err.(type) {
+	case nil:
+		return slog.Any("error", nil)
+	case *Err:
+		return slog.Attr{Key: "error", Value: e.LogValue()}
+	case *Frozen:
+		return slog.Attr{Key: "error", Value: e.LogValue()}
+	}
+
+	if e := errOf(err); e != nil {
+		attrs := append([]slog.Attr{slog.String("error", err.Error())}, e.LogValue().Group()...)
+		return slog.Attr{Key: "error", Value: slog.GroupValue(attrs...)}
+	}
+	return slog.String("error", err.Error())
+}
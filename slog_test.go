@@ -0,0 +1,235 @@
+package xerr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// logJSON logs args with a JSON handler and returns the decoded record.
+func logJSON(t *testing.T, args ...any) map[string]any {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	logger.Error("request failed", args...)
+
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	return record
+}
+
+// setLogStackTrace sets whether the stack trace is logged for the duration of
+// the test.
+func setLogStackTrace(t *testing.T, enabled bool) {
+	t.Helper()
+
+	prev := SetLogStackTrace(enabled)
+	t.Cleanup(func() { SetLogStackTrace(prev) })
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of LogValue()
+//
+// ----------------------------------------------------------------------------
+
+func TestErr_LogValue(t *testing.T) {
+	now := time.Now().UnixMicro()
+	e := &Err{
+		Value:     errors.New("test"),
+		Code:      500,
+		Msg:       "My error message",
+		File:      "error_test.go",
+		Line:      26,
+		Timestamp: now,
+	}
+
+	value := e.LogValue()
+
+	assert.Equal(t, slog.KindGroup, value.Kind())
+	assert.Equal(t, []slog.Attr{
+		slog.String("value", "test"),
+		slog.Int("code", 500),
+		slog.String("msg", "My error message"),
+		slog.String("source", "error_test.go:26"),
+		slog.Time("timestamp", time.UnixMicro(now)),
+	}, value.Group())
+}
+
+func TestErr_LogValue_PublicMsg(t *testing.T) {
+	e := Make(errors.New("test"), WithMsg("row 42 not found"), WithPublicMsg("User not found"))
+
+	record := logJSON(t, "error", e)["error"].(map[string]any)
+
+	assert.Equal(t, "row 42 not found", record["msg"])
+	assert.Equal(t, "User not found", record["public_msg"])
+}
+
+func TestErr_LogValue_JSONHandler(t *testing.T) {
+	root := New(errors.New("connection refused"), "db error", nil, 0, nil)
+	e := New(errors.New("query failed"), "cannot fetch user", map[string]int{"id": 42}, 500, root)
+
+	record := logJSON(t, "error", e)
+
+	logged := record["error"].(map[string]any)
+	assert.Equal(t, "query failed", logged["value"])
+	assert.Equal(t, float64(500), logged["code"])
+	assert.Equal(t, "cannot fetch user", logged["msg"])
+	assert.Contains(t, logged["source"], "slog_test.go:")
+	assert.Equal(t, map[string]any{"id": float64(42)}, logged["details"])
+	assert.NotContains(t, logged, "stack_trace")
+
+	prev := logged["prev"].(map[string]any)
+	assert.Equal(t, "connection refused", prev["value"])
+	assert.Equal(t, "db error", prev["msg"])
+	assert.NotContains(t, prev, "details")
+	assert.NotContains(t, prev, "prev")
+}
+
+func TestErr_LogValue_WithStackTrace(t *testing.T) {
+	setLogStackTrace(t, true)
+
+	e := New(errors.New("test"), "My error message", nil, 0, nil)
+	record := logJSON(t, "error", e)
+
+	logged := record["error"].(map[string]any)
+	frames := logged["stack_trace"].([]any)
+	assert.NotEmpty(t, frames)
+	assert.Contains(t, frames[0].(map[string]any)["function"], "TestErr_LogValue_WithStackTrace")
+}
+
+func TestErr_LogValue_WithStackTrace_NoStack(t *testing.T) {
+	setLogStackTrace(t, true)
+
+	e := Make(errors.New("test"), WithoutStack())
+	record := logJSON(t, "error", e)
+
+	assert.NotContains(t, record["error"], "stack_trace")
+}
+
+func TestErr_LogValue_Causes(t *testing.T) {
+	record := logJSON(t, "error", newTestTree())
+
+	causes := record["error"].(map[string]any)["causes"].(map[string]any)
+	assert.Len(t, causes, 2)
+	assert.Equal(t, "timeout", causes["0"].(map[string]any)["value"])
+	assert.Equal(t, "dns failure", causes["1"].(map[string]any)["prev"].(map[string]any)["value"])
+}
+
+func TestErr_LogValue_Nil(t *testing.T) {
+	var e *Err
+	assert.Equal(t, slog.AnyValue(nil), e.LogValue())
+}
+
+func TestSetLogStackTrace_ReturnsPrevious(t *testing.T) {
+	setLogStackTrace(t, false)
+
+	assert.False(t, SetLogStackTrace(true))
+	assert.True(t, SetLogStackTrace(false))
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Level() and Log()
+//
+// ----------------------------------------------------------------------------
+
+func TestLevel(t *testing.T) {
+	warn := Make(errors.New("not found"), WithSeverity(SeverityWarn))
+
+	assert.Equal(t, slog.LevelWarn, Level(warn))
+	assert.Equal(t, slog.LevelWarn, Level(warn.Freeze()))
+	assert.Equal(t, slog.LevelWarn, Level(fmt.Errorf("handler: %w", warn)))
+	assert.Equal(t, LevelCritical, Level(Make(errors.New("corrupted"), WithSeverity(SeverityCritical), WithPrev(warn))))
+	assert.Equal(t, slog.LevelError, Level(NewSimple(errors.New("test"), "", nil)))
+	assert.Equal(t, slog.LevelError, Level(errors.New("test")))
+	assert.Equal(t, slog.LevelError, Level(nil))
+}
+
+func TestLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	e := Make(errors.New("not found"), WithSeverity(SeverityInfo))
+
+	args := make([]any, 2, 3)
+	args[0], args[1] = "path", "/users/42"
+	Log(context.Background(), logger, "request failed", e, args...)
+
+	var record map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "INFO", record["level"])
+	assert.Equal(t, "request failed", record["msg"])
+	assert.Equal(t, "/users/42", record["path"])
+	assert.Equal(t, "not found", record["error"].(map[string]any)["value"])
+	assert.Nil(t, args[:cap(args)][2], "args must not be modified")
+}
+
+func TestLog_DefaultLogger(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	defer slog.SetDefault(prev)
+
+	Log(context.Background(), nil, "request failed", Make(errors.New("corrupted"), WithSeverity(SeverityCritical)))
+
+	assert.Contains(t, buf.String(), `"level":"ERROR+4"`)
+}
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Attr()
+//
+// ----------------------------------------------------------------------------
+
+func TestAttr(t *testing.T) {
+	e := New(errors.New("test"), "My error message", nil, 0, nil)
+	attr := Attr(e)
+
+	assert.Equal(t, "error", attr.Key)
+	assert.Equal(t, slog.KindGroup, attr.Value.Kind())
+
+	record := logJSON(t, attr)
+	assert.Equal(t, "test", record["error"].(map[string]any)["value"])
+}
+
+func TestAttr_FrozenAndWrapped(t *testing.T) {
+	e := New(errors.New("test"), "My error message", nil, 0, nil)
+	want := logJSON(t, Attr(e))["error"].(map[string]any)
+
+	attr := Attr(e.Freeze())
+	assert.Equal(t, slog.KindGroup, attr.Value.Kind())
+	assert.Equal(t, want, logJSON(t, attr)["error"])
+
+	for _, err := range []error{fmt.Errorf("handler: %w", e), fmt.Errorf("handler: %w", e.Freeze())} {
+		attr := Attr(err)
+		assert.Equal(t, slog.KindGroup, attr.Value.Kind(), err.Error())
+
+		got := logJSON(t, attr)["error"].(map[string]any)
+		assert.Equal(t, err.Error(), got["error"])
+		assert.Contains(t, got["error"], "handler: ")
+		delete(got, "error")
+		assert.Equal(t, want, got, err.Error())
+	}
+}
+
+func TestAttr_StandardError(t *testing.T) {
+	attr := Attr(errors.New("test"))
+
+	assert.Equal(t, slog.String("error", "test"), attr)
+}
+
+func TestAttr_Nil(t *testing.T) {
+	var e *Err
+
+	assert.Equal(t, slog.Any("error", nil), Attr(nil))
+	assert.Equal(t, "error", Attr(e).Key)
+	assert.Nil(t, Attr(e).Value.Any())
+}
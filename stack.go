@@ -0,0 +1,113 @@
+package xerr
+
+import (
+	"encoding/json"
+	"runtime"
+	"slices"
+	"sync"
+)
+
+// maxStackDepth is the maximum number of frames captured in a [Stack].
+const maxStackDepth = 64
+
+// Frame is a single symbolized frame of a [Stack].
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// Stack is a stack trace captured as program counters with
+// [runtime.Callers]. Program counters are only symbolized into frames the
+// first time they are read or serialized. A Stack decoded from JSON only
+// holds its frames.
+//
+// The first frame of the stack is the call site of the error, so the frames
+// of xerr itself and the goroutine header are never included.
+type Stack struct {
+	pcs    []uintptr
+	once   sync.Once
+	frames []Frame
+}
+
+// newStack captures the stack of the calling goroutine. The skip parameter
+// has the same meaning as in [runtime.Caller]: 0 identifies the caller of
+// newStack.
+func newStack(skip int) *Stack {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(skip+2, pcs[:])
+
+	return &Stack{pcs: slices.Clone(pcs[:n])}
+}
+
+// Frames returns the symbolized frames of the stack, innermost first.
+// Returns nil if called on a nil pointer.
+func (s *Stack) Frames() []Frame {
+	if s == nil {
+		return nil
+	}
+
+	s.once.Do(func() {
+		if len(s.pcs) == 0 {
+			return
+		}
+
+		frames := runtime.CallersFrames(s.pcs)
+		s.frames = make([]Frame, 0, len(s.pcs))
+		for {
+			frame, more := frames.Next()
+			s.frames = append(s.frames, Frame{
+				Function: frame.Function,
+				File:     frame.File,
+				Line:     frame.Line,
+			})
+			if !more {
+				break
+			}
+		}
+	})
+
+	return slices.Clone(s.frames)
+}
+
+// clone returns a copy of s sharing its program counters, or nil if s is nil.
+// The frames of a stack decoded from JSON, which has no program counters, are
+// copied.
+func (s *Stack) clone() *Stack {
+	if s == nil {
+		return nil
+	}
+
+	c := &Stack{pcs: s.pcs}
+	if len(s.pcs) == 0 {
+		frames := s.Frames()
+		c.once.Do(func() {
+			c.frames = frames
+		})
+	}
+	return c
+}
+
+// MarshalJSON implements [json.Marshaler]. The stack is encoded as a JSON
+// array of frames.
+func (s *Stack) MarshalJSON() ([]byte, error) {
+	frames := s.Frames()
+	if frames == nil {
+		frames = []Frame{}
+	}
+	return json.Marshal(frames)
+}
+
+// UnmarshalJSON implements [json.Unmarshaler], restoring the frames encoded
+// by [Stack.MarshalJSON].
+func (s *Stack) UnmarshalJSON(data []byte) error {
+	var frames []Frame
+	if err := json.Unmarshal(data, &frames); err != nil {
+		return err
+	}
+
+	s.once.Do(func() {
+		s.frames = frames
+	})
+	return nil
+}
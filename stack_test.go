@@ -0,0 +1,102 @@
+package xerr
+
+import (
+	"encoding/json"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ----------------------------------------------------------------------------
+//
+// Tests of Stack
+//
+// ----------------------------------------------------------------------------
+
+func TestStack_Frames_StartsAtCallSite(t *testing.T) {
+	_, _, wantLine, _ := runtime.Caller(0)
+	wantLine += 2
+	err := New(errors.New("test"), "My error message", nil, 0, nil)
+
+	frames := err.StackTrace.Frames()
+
+	assert.NotEmpty(t, frames)
+	assert.True(t, strings.HasSuffix(frames[0].Function, "TestStack_Frames_StartsAtCallSite"))
+	assert.True(t, strings.Contains(frames[0].File, "stack_test.go"))
+	assert.Equal(t, wantLine, frames[0].Line)
+	assert.Equal(t, err.File, frames[0].File)
+	assert.Equal(t, err.Line, frames[0].Line)
+}
+
+func TestStack_Frames_ExcludesXerrFrames(t *testing.T) {
+	err := NewSimple(errors.New("test"), "My error message", nil)
+
+	for _, frame := range err.Frames() {
+		assert.False(t, strings.HasSuffix(frame.Function, "xerr.Make"))
+		assert.False(t, strings.HasSuffix(frame.Function, "xerr.NewSimple"))
+		assert.False(t, strings.HasSuffix(frame.Function, "xerr.newStack"))
+	}
+}
+
+func TestStack_Frames_WithSkip(t *testing.T) {
+	err := Make(errors.New("test"), WithSkip(0))
+
+	frames := err.Frames()
+
+	assert.NotEmpty(t, frames)
+	assert.True(t, strings.HasSuffix(frames[0].Function, "xerr.Make"))
+}
+
+func TestStack_Frames_Nil(t *testing.T) {
+	var s *Stack
+	assert.Nil(t, s.Frames())
+
+	var err *Err
+	assert.Nil(t, err.Frames())
+
+	err = Make(errors.New("test"), WithoutStack())
+	assert.Nil(t, err.Frames())
+}
+
+func TestStack_Frames_Empty(t *testing.T) {
+	s := &Stack{}
+	assert.Nil(t, s.Frames())
+}
+
+func TestStack_Frames_ReturnsCopy(t *testing.T) {
+	err := New(errors.New("test"), "My error message", nil, 0, nil)
+
+	frames := err.Frames()
+	frames[0].Function = "changed"
+
+	assert.NotEqual(t, "changed", err.Frames()[0].Function)
+}
+
+func TestStack_MarshalJSON(t *testing.T) {
+	err := New(errors.New("test"), "My error message", nil, 0, nil)
+
+	result, jsonErr := json.Marshal(err.StackTrace)
+	assert.NoError(t, jsonErr)
+
+	var frames []Frame
+	assert.NoError(t, json.Unmarshal(result, &frames))
+	assert.Equal(t, err.Frames(), frames)
+}
+
+func TestStack_MarshalJSON_Empty(t *testing.T) {
+	result, err := json.Marshal(&Stack{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "[]", string(result))
+}
+
+func TestStack_UnmarshalJSON(t *testing.T) {
+	var s Stack
+	err := json.Unmarshal([]byte(`[{"function":"main.main","file":"main.go","line":12}]`), &s)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Frame{{Function: "main.main", File: "main.go", Line: 12}}, s.Frames())
+}